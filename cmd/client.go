@@ -1,16 +1,26 @@
 package main
 
 import (
+	"flag"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/faiface/beep"
 	"github.com/sirupsen/logrus"
 
+	"network-audio/pkg/audio"
+	"network-audio/pkg/calibration"
+	"network-audio/pkg/capture"
 	"network-audio/pkg/client"
 	"network-audio/pkg/client/player"
+	"network-audio/pkg/client/speaker"
+	"network-audio/pkg/dsp"
 	"network-audio/pkg/logx"
+	"network-audio/pkg/messages"
+	"network-audio/pkg/timex"
 )
 
 func main() {
@@ -24,28 +34,142 @@ func main() {
 	)
 	logger.SetOutput(os.Stderr)
 
-	args := os.Args[1:]
+	outputLatency := flag.Duration("output-latency", 0, "delay added by the audio output device, e.g. 40ms")
+	hostOutputLatency := flag.String("set-output-latency", "", "output latency offset to set at the server for the clients of a host, as <host>=<duration>")
+	calibrationRecording := flag.String("calibration-recording", "", "wav file used as recording when this client is the calibration reference")
+	calibrationMicrophone := flag.Bool("calibration-microphone", false, "record the microphone when this client is the calibration reference")
+	microphoneCommand := flag.String("microphone-command", strings.Join(calibration.DefaultMicrophoneCommand, " "), "command that writes the microphone as raw signed 16 bit little endian mono pcm, {rate} is replaced by the sample rate")
+	microphoneLatency := flag.Duration("microphone-latency", 0, "delay added by the audio input device, e.g. 20ms")
+	channels := flag.String("channels", "stereo", "channels to play: stereo, mono, left, right or a position like fl, fc, lfe, sl")
+	sampleRate := flag.Int("sample-rate", 44100, "sample rate of the audio output, streams with another rate are resampled")
+	progress := flag.Bool("progress", false, "log the playback position")
+	dspConfig := flag.String("dsp", "", "dsp config file with equalizer, bass, treble, high-pass and limiter")
+	record := flag.String("record", "", "wav file to record the samples handed to the speaker to")
+	recordTiming := flag.Bool("record-timing", false, "write the time the recorded blocks became audible to <record>.csv")
+	output := flag.String("output", "speaker", "audio output: speaker, null, pcm for raw pcm on stdout, pcm:<file> or wav:<file>")
+	flag.Parse()
+
+	args := flag.Args()
 
 	if len(args) < 1 {
-		logger.Fatal("Usage: client <host>")
+		logger.Fatal("Usage: client [options] <host>")
 	}
 
 	host := args[0]
 
+	channelMap, err := audio.ParseChannelMap(*channels)
+
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	effects := dsp.Config{}
+
+	if *dspConfig != "" {
+		effects, err = dsp.ReadConfig(*dspConfig)
+
+		if err != nil {
+			logger.Fatal(err)
+		}
+	}
+
 	clog := logx.Scope(logger, "client")
 
 	cl := player.NewClock(time.Duration(5) * time.Millisecond)
 
-	p := player.New(
-		logx.Component(logger, "player"),
-		cl,
-	)
+	var out player.Output
+
+	if *output == "speaker" {
+		out = speaker.NewOutput()
+	} else {
+		out, err = player.ParseOutput(*output)
+
+		if err != nil {
+			logger.Fatal(err)
+		}
+	}
+
+	defer out.Close()
+
+	format := beep.Format{SampleRate: beep.SampleRate(*sampleRate), Precision: 2, NumChannels: 2}
+
+	playerOptions := []player.Option{
+		player.WithOutputLatency(*outputLatency),
+		player.WithChannelMap(channelMap),
+		player.WithDspConfig(effects),
+		player.WithFormat(format),
+		player.WithOutput(out),
+	}
+
+	if *record != "" {
+		recording, err := capture.Create(*record, format, *recordTiming)
+
+		if err != nil {
+			logger.Fatalf("create recording error: %s", err)
+		}
 
-	c := client.New(
-		clog, cl, p, host,
-		client.WithReconnectInterval(time.Second*1),
+		defer recording.Close()
+
+		playerOptions = append(playerOptions, player.WithRecording(recording))
+	}
+
+	p := player.New(logx.Component(logger, "player"), cl, playerOptions...)
+
+	options := []client.ClientOption{
+		client.WithReconnectInterval(time.Second * 1),
 		client.WithReconnectMaxTimes(30),
-	)
+		client.WithNowPlayingHandler(
+			func(m *messages.NowPlaying) {
+				title := m.Title
+
+				if m.Artist != "" {
+					title = m.Artist + " - " + title
+				}
+
+				if m.Album != "" {
+					title += " (" + m.Album + ")"
+				}
+
+				clog.Infof("now playing: %s [%s]", title, timex.FormatDuration(time.Duration(m.Duration)))
+			},
+		),
+	}
+
+	if *progress {
+		options = append(
+			options,
+			client.WithPlaybackStateHandler(
+				func(m *messages.PlaybackState) {
+					clog.Infof(
+						"%s %s / %s",
+						m.State.String(),
+						timex.FormatDuration(time.Duration(m.Position)),
+						timex.FormatDuration(time.Duration(m.Duration)),
+					)
+				},
+			),
+		)
+	}
+
+	if *hostOutputLatency != "" {
+		latencyHost, value, found := strings.Cut(*hostOutputLatency, "=")
+		latency, err := time.ParseDuration(value)
+
+		if !found || err != nil {
+			logger.Fatalf("invalid output latency %q, expected <host>=<duration>", *hostOutputLatency)
+		}
+
+		options = append(options, client.WithHostOutputLatency(latencyHost, latency))
+	}
+
+	if *calibrationRecording != "" {
+		options = append(options, client.WithRecorder(calibration.NewWavRecorder(*calibrationRecording)))
+	} else if *calibrationMicrophone {
+		recorder := calibration.NewMicrophoneRecorder(strings.Fields(*microphoneCommand), *microphoneLatency)
+		options = append(options, client.WithRecorder(recorder))
+	}
+
+	c := client.New(clog, cl, p, host, options...)
 
 	go func(c *client.Client) {
 		signalChan := make(chan os.Signal, 1)
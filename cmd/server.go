@@ -1,20 +1,51 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/faiface/beep"
 	"github.com/panjf2000/gnet/v2"
 	"github.com/sirupsen/logrus"
 
+	"network-audio/pkg/capture"
+	"network-audio/pkg/dsp"
 	"network-audio/pkg/logx"
 	"network-audio/pkg/server"
+	"network-audio/pkg/server/player"
 )
 
+// hostFlags collects repeated <host>=<value> flags.
+type hostFlags map[string]string
+
+func (f hostFlags) String() string {
+	parts := []string{}
+
+	for host, value := range f {
+		parts = append(parts, host+"="+value)
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func (f hostFlags) Set(value string) error {
+	host, v, found := strings.Cut(value, "=")
+
+	if !found {
+		return fmt.Errorf("expected <host>=<value>, got %q", value)
+	}
+
+	f[host] = v
+
+	return nil
+}
+
 func main() {
 	logger := logrus.New()
 	logger.SetLevel(logrus.InfoLevel)
@@ -26,10 +57,94 @@ func main() {
 	)
 	logger.SetOutput(os.Stderr)
 
+	outputLatencies := hostFlags{}
+	flag.Var(outputLatencies, "output-latency", "output latency offset of a client, as <host>=<duration> (repeatable)")
+	dspConfigs := hostFlags{}
+	flag.Var(dspConfigs, "dsp", "dsp config file of a client, as <host>=<file.json> (repeatable)")
+	calibrationReference := flag.String("calibrate", "", "host of the client that records the calibration of all other clients")
+	sampleRate := flag.Int("sample-rate", 44100, "sample rate of the stream, e.g. 48000 or 96000")
+	crossfade := flag.Duration("crossfade", 0, "duration of the crossfade between two files, e.g. 3s")
+	normalize := flag.Bool("normalize", false, "normalize the loudness of the files")
+	loudnessTarget := flag.Float64("loudness-target", -18, "target loudness of the normalization in LUFS")
+	loudnessCache := flag.String("loudness-cache", "", "file to store the loudness analysis in")
+	httpBuffer := flag.Int("http-buffer", 256*1024, "input buffer of http streams in bytes")
+	stallTimeout := flag.Duration("stall-timeout", time.Second*10, "time without data after which http streams reconnect")
+	record := flag.String("record", "", "wav file to record the broadcast to")
+	recordTiming := flag.Bool("record-timing", false, "write the schedule of the recorded blocks to <record>.csv")
+	httpOutput := flag.String("http-output", "", "address to serve the broadcast as wav stream on, e.g. :8000")
+	webSocket := flag.String("websocket", "", "address to serve the protocol over websockets and the browser client on, e.g. :8080")
+	slowClientTimeout := flag.Duration("slow-client-timeout", time.Second*5, "time after which clients that stay behind are disconnected")
+	history := flag.Duration("history", time.Second*2, "audio kept to send to new clients as burst, 0 disables it")
+	presentationDelay := flag.Duration("presentation-delay", time.Millisecond*500, "time between sending audio and its play time on the clients")
+	flag.Parse()
+
 	addr := fmt.Sprintf("tcp://:%d", 3000)
 	slog := logx.Scope(logger, "server")
 
-	svr := server.New(slog, addr)
+	options := []server.Option{
+		server.WithSlowClientTimeout(*slowClientTimeout),
+		server.WithHistory(*history),
+		server.WithPlayerOptions(
+			player.WithFormat(beep.Format{SampleRate: beep.SampleRate(*sampleRate), Precision: 2, NumChannels: 2}),
+			player.WithCrossfade(*crossfade),
+			player.WithHTTPBuffer(*httpBuffer, *stallTimeout),
+			player.WithPresentationDelay(*presentationDelay),
+		),
+	}
+
+	if *normalize {
+		options = append(options, server.WithPlayerOptions(player.WithLoudnessNormalization(*loudnessTarget, *loudnessCache)))
+	}
+
+	if *httpOutput != "" {
+		options = append(options, server.WithHTTPOutput(*httpOutput))
+	}
+
+	if *webSocket != "" {
+		options = append(options, server.WithWebSocket(*webSocket))
+	}
+
+	if flag.NArg() > 0 {
+		options = append(options, server.WithFiles(flag.Args()...))
+	}
+
+	for host, value := range outputLatencies {
+		latency, err := time.ParseDuration(value)
+
+		if err != nil {
+			logger.Fatalf("invalid output latency of %s: %s", host, err)
+		}
+
+		options = append(options, server.WithOutputLatency(host, latency))
+	}
+
+	for host, path := range dspConfigs {
+		config, err := dsp.ReadConfig(path)
+
+		if err != nil {
+			logger.Fatalf("invalid dsp config of %s: %s", host, err)
+		}
+
+		options = append(options, server.WithDspConfig(host, config))
+	}
+
+	if *calibrationReference != "" {
+		options = append(options, server.WithCalibrationReference(*calibrationReference))
+	}
+
+	if *record != "" {
+		recording, err := capture.Create(*record, beep.Format{SampleRate: beep.SampleRate(*sampleRate), Precision: 2, NumChannels: 2}, *recordTiming)
+
+		if err != nil {
+			logger.Fatalf("create recording error: %s", err)
+		}
+
+		defer recording.Close()
+
+		options = append(options, server.WithRecording(recording))
+	}
+
+	svr := server.New(slog, addr, options...)
 
 	go func(svr *server.Server) {
 		signalChan := make(chan os.Signal, 1)
@@ -5,6 +5,8 @@ go 1.18
 require (
 	github.com/faiface/beep v1.1.0
 	github.com/golang/protobuf v1.5.2
+	github.com/gorilla/websocket v1.5.0
+	github.com/jfreymuth/oggvorbis v1.0.5
 	github.com/oklog/ulid/v2 v2.0.2
 	github.com/panjf2000/gnet/v2 v2.0.3
 	github.com/pkg/errors v0.9.1
@@ -15,6 +17,7 @@ require (
 require (
 	github.com/hajimehoshi/go-mp3 v0.3.0 // indirect
 	github.com/hajimehoshi/oto v0.7.1 // indirect
+	github.com/jfreymuth/vorbis v1.0.2 // indirect
 	go.uber.org/atomic v1.9.0 // indirect
 	go.uber.org/multierr v1.8.0 // indirect
 	go.uber.org/zap v1.21.0 // indirect
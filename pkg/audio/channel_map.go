@@ -0,0 +1,103 @@
+package audio
+
+import (
+	"math"
+	"strings"
+)
+
+// ChannelMap maps the channels of a sample to the two outputs of a stereo speaker.
+type ChannelMap func(data []float64, layout Layout) [2]float64
+
+var downmixGains = map[Position][2]float64{
+	FrontLeft:    {1, 0},
+	FrontRight:   {0, 1},
+	FrontCenter:  {math.Sqrt2 / 2, math.Sqrt2 / 2},
+	LowFrequency: {0, 0},
+	BackLeft:     {math.Sqrt2 / 2, 0},
+	BackRight:    {0, math.Sqrt2 / 2},
+	SideLeft:     {math.Sqrt2 / 2, 0},
+	SideRight:    {0, math.Sqrt2 / 2},
+}
+
+// StereoMap plays stereo as is and downmixes every other layout to stereo.
+func StereoMap(data []float64, layout Layout) [2]float64 {
+	if len(data) == 0 {
+		return [2]float64{}
+	}
+
+	if len(data) == 1 {
+		return [2]float64{data[0], data[0]}
+	}
+
+	positions := layout.Positions()
+
+	if len(positions) != len(data) {
+		return [2]float64{data[0], data[1]}
+	}
+
+	var out, total [2]float64
+
+	for i, position := range positions {
+		gains := downmixGains[position]
+
+		out[0] += data[i] * gains[0]
+		out[1] += data[i] * gains[1]
+		total[0] += gains[0]
+		total[1] += gains[1]
+	}
+
+	// normalize, so a signal on all channels does not clip
+	if total[0] > 1 {
+		out[0] /= total[0]
+	}
+
+	if total[1] > 1 {
+		out[1] /= total[1]
+	}
+
+	return out
+}
+
+// MonoMap plays the downmix of all channels on both outputs.
+func MonoMap(data []float64, layout Layout) [2]float64 {
+	stereo := StereoMap(data, layout)
+	mono := (stereo[0] + stereo[1]) / 2
+
+	return [2]float64{mono, mono}
+}
+
+// PositionMap plays a single channel on both outputs, e.g. to build stereo pairs or surround setups from single speakers.
+// Layouts without the position are played as mono downmix.
+func PositionMap(position Position) ChannelMap {
+	return func(data []float64, layout Layout) [2]float64 {
+		index := layout.Index(position)
+
+		if index < 0 || index >= len(data) {
+			return MonoMap(data, layout)
+		}
+
+		return [2]float64{data[index], data[index]}
+	}
+}
+
+// ParseChannelMap parses "stereo", "mono", "left", "right" or a channel position like "fc" or "lfe".
+func ParseChannelMap(name string) (ChannelMap, error) {
+	switch strings.ToLower(name) {
+	case "stereo":
+		return StereoMap, nil
+	case "mono":
+		return MonoMap, nil
+	case "left":
+		return PositionMap(FrontLeft), nil
+	case "right":
+		return PositionMap(FrontRight), nil
+	}
+
+	position, err := ParsePosition(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return PositionMap(position), nil
+}
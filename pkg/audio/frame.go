@@ -0,0 +1,22 @@
+package audio
+
+import (
+	"time"
+)
+
+// MaxChannels is the amount of channels of the largest layout.
+const MaxChannels = 8
+
+// Frame holds one sample of every channel by value, so frames can be buffered without allocations.
+type Frame struct {
+	Data [MaxChannels]float64
+	// The amount of channels used in Data.
+	Channels int
+	Layout   Layout
+	Time     time.Time
+}
+
+// Samples returns the samples of the used channels.
+func (f *Frame) Samples() []float64 {
+	return f.Data[:f.Channels]
+}
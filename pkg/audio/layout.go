@@ -0,0 +1,99 @@
+package audio
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Layout describes the channels of a sample, the values match messages.ChannelLayout.
+type Layout int
+
+const (
+	Unspecified Layout = iota
+	Mono
+	Stereo
+	Quad
+	Surround51
+	Surround71
+)
+
+// Position is the speaker position of a single channel.
+type Position int
+
+const (
+	FrontLeft Position = iota
+	FrontRight
+	FrontCenter
+	LowFrequency
+	BackLeft
+	BackRight
+	SideLeft
+	SideRight
+)
+
+var positionNames = map[Position]string{
+	FrontLeft:    "fl",
+	FrontRight:   "fr",
+	FrontCenter:  "fc",
+	LowFrequency: "lfe",
+	BackLeft:     "bl",
+	BackRight:    "br",
+	SideLeft:     "sl",
+	SideRight:    "sr",
+}
+
+var layoutPositions = map[Layout][]Position{
+	Mono:       {FrontCenter},
+	Stereo:     {FrontLeft, FrontRight},
+	Quad:       {FrontLeft, FrontRight, BackLeft, BackRight},
+	Surround51: {FrontLeft, FrontRight, FrontCenter, LowFrequency, SideLeft, SideRight},
+	Surround71: {FrontLeft, FrontRight, FrontCenter, LowFrequency, BackLeft, BackRight, SideLeft, SideRight},
+}
+
+// Positions returns the position of each channel of the layout.
+func (l Layout) Positions() []Position {
+	return layoutPositions[l]
+}
+
+// Channels returns the amount of channels of the layout.
+func (l Layout) Channels() int {
+	return len(layoutPositions[l])
+}
+
+// Index returns the channel index of the position in the layout or -1 if the layout has no such channel.
+func (l Layout) Index(position Position) int {
+	for i, p := range l.Positions() {
+		if p == position {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// LayoutOf returns the default layout for the given amount of channels.
+func LayoutOf(channels int) Layout {
+	for layout := Mono; layout <= Surround71; layout++ {
+		if layout.Channels() == channels {
+			return layout
+		}
+	}
+
+	return Unspecified
+}
+
+func (p Position) String() string {
+	return positionNames[p]
+}
+
+// ParsePosition parses a position name like "fl", "fc" or "lfe".
+func ParsePosition(name string) (Position, error) {
+	for position, positionName := range positionNames {
+		if positionName == strings.ToLower(name) {
+			return position, nil
+		}
+	}
+
+	return 0, errors.Errorf("unknown channel position: %s", name)
+}
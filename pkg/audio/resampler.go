@@ -0,0 +1,95 @@
+package audio
+
+import (
+	"github.com/faiface/beep"
+)
+
+// Resampler converts consecutive blocks of frames from one sample rate to another using linear interpolation.
+// It keeps the last frame of the previous block, so the blocks are resampled as one continuous stream.
+type Resampler struct {
+	from beep.SampleRate
+	to   beep.SampleRate
+
+	// input frames per output frame
+	step float64
+
+	// position of the next output frame relative to the start of the next block, -1 refers to the last frame
+	position float64
+	last     []float64
+}
+
+func NewResampler(from, to beep.SampleRate) *Resampler {
+	return &Resampler{
+		from:     from,
+		to:       to,
+		step:     float64(from) / float64(to),
+		position: 0,
+	}
+}
+
+func (r *Resampler) From() beep.SampleRate {
+	return r.from
+}
+
+func (r *Resampler) To() beep.SampleRate {
+	return r.to
+}
+
+// Resample interpolates the output frames of the block. For each frame it calls fn with the frame data and its
+// position in input frames relative to the first frame of the block, the position is negative for frames that lie
+// between the previous block and this one.
+func (r *Resampler) Resample(frames [][]float64, fn func(data []float64, position float64)) {
+	if len(frames) == 0 {
+		return
+	}
+
+	// without a previous frame, the first output frame starts at the block
+	if r.last == nil && r.position < 0 {
+		r.position = 0
+	}
+
+	frame := func(index int) []float64 {
+		if index < 0 {
+			return r.last
+		}
+
+		return frames[index]
+	}
+
+	for r.position <= float64(len(frames)-1) {
+		index := int(r.position)
+
+		if r.position < 0 {
+			index = -1
+		}
+
+		fraction := r.position - float64(index)
+		current := frame(index)
+		data := make([]float64, len(current))
+
+		if fraction == 0 || index+1 >= len(frames) {
+			copy(data, current)
+		} else {
+			next := frames[index+1]
+
+			for channel := range data {
+				if channel < len(next) {
+					data[channel] = current[channel] + (next[channel]-current[channel])*fraction
+				}
+			}
+		}
+
+		fn(data, r.position)
+
+		r.position += r.step
+	}
+
+	r.position -= float64(len(frames))
+	r.last = frames[len(frames)-1]
+}
+
+// Reset forgets the previous block, e.g. after a gap in the stream.
+func (r *Resampler) Reset() {
+	r.position = 0
+	r.last = nil
+}
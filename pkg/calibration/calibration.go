@@ -0,0 +1,38 @@
+// Package calibration measures the acoustic output delay of a client.
+//
+// The server plays the calibration Signal through a single client while a reference client records the room.
+// The reference client locates the signal in the recording and reports the delay between the scheduled
+// and the audible start of the signal. Since all clients are measured against the same reference, the input
+// latency of the recording device shifts every measurement equally and does not affect their alignment.
+package calibration
+
+import (
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/pkg/errors"
+)
+
+// MinConfidence is the minimum normalized correlation a measurement needs to be trusted.
+const MinConfidence = 0.3
+
+// MaxDelay is the maximum output delay that can be measured.
+const MaxDelay = time.Millisecond * 500
+
+var ErrSignalNotFound = errors.New("calibration signal not found in recording")
+
+// Measure locates the calibration signal in a recording that started at the scheduled signal start.
+func Measure(recording []float64, rate beep.SampleRate) (time.Duration, float64, error) {
+	offset, confidence := FindOffset(recording, Signal(rate))
+
+	if confidence < MinConfidence {
+		return 0, confidence, ErrSignalNotFound
+	}
+
+	return rate.D(offset), confidence, nil
+}
+
+// RecordingDuration returns how long the reference client has to record to capture the signal with the maximum delay.
+func RecordingDuration() time.Duration {
+	return SignalDuration() + MaxDelay
+}
@@ -0,0 +1,202 @@
+package calibration
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/wav"
+)
+
+const rate = beep.SampleRate(44100)
+
+func delayed(delay time.Duration, noise float64) []float64 {
+	signal := Signal(rate)
+	recording := make([]float64, rate.N(RecordingDuration()))
+	offset := rate.N(delay)
+	random := rand.New(rand.NewSource(1))
+
+	for i := range recording {
+		if i >= offset && i-offset < len(signal) {
+			recording[i] = signal[i-offset] * 0.5
+		}
+
+		recording[i] += (random.Float64()*2 - 1) * noise
+	}
+
+	return recording
+}
+
+func TestMeasure(t *testing.T) {
+	t.Run(
+		"should find the delay of the signal",
+		func(t *testing.T) {
+			delay, confidence, err := Measure(delayed(time.Millisecond*123, 0.1), rate)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := delay - time.Millisecond*123; diff < -time.Millisecond || diff > time.Millisecond {
+				t.Fatalf("delay is %s, expected 123ms", delay)
+			}
+
+			if confidence < MinConfidence {
+				t.Fatalf("confidence %f is too low", confidence)
+			}
+		},
+	)
+
+	t.Run(
+		"should fail if the recording only contains noise",
+		func(t *testing.T) {
+			recording := make([]float64, rate.N(RecordingDuration()))
+			random := rand.New(rand.NewSource(1))
+
+			for i := range recording {
+				recording[i] = random.Float64()*2 - 1
+			}
+
+			_, _, err := Measure(recording, rate)
+
+			if err != ErrSignalNotFound {
+				t.Fatalf("expected ErrSignalNotFound, got %v", err)
+			}
+		},
+	)
+}
+
+func TestWavRecorder_Record(t *testing.T) {
+	t.Run(
+		"should measure the delay from a wav file",
+		func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "recording.wav")
+			file, err := os.Create(path)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer file.Close()
+
+			recording := delayed(time.Millisecond*40, 0.05)
+			samples := make([][2]float64, len(recording))
+
+			for i, v := range recording {
+				samples[i] = [2]float64{v, v}
+			}
+
+			format := beep.Format{SampleRate: rate, NumChannels: 2, Precision: 2}
+			err = wav.Encode(file, beep.Take(len(samples), beep.StreamerFunc(
+				func(s [][2]float64) (int, bool) {
+					n := copy(s, samples)
+					samples = samples[n:]
+
+					return n, n > 0
+				},
+			)), format)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			recorded, err := NewWavRecorder(path).Record(time.Now(), RecordingDuration(), rate)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			delay, _, err := Measure(recorded, rate)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := delay - time.Millisecond*40; diff < -time.Millisecond || diff > time.Millisecond {
+				t.Fatalf("delay is %s, expected 40ms", delay)
+			}
+		},
+	)
+}
+
+// TestCaptureProcess plays a capture command for TestMicrophoneRecorder_Record, it writes the samples in real time
+// with a click at the time in the environment.
+func TestCaptureProcess(t *testing.T) {
+	click, err := strconv.ParseInt(os.Getenv("CALIBRATION_TEST_CLICK"), 10, 64)
+
+	if err != nil {
+		return
+	}
+
+	captureRate := beep.SampleRate(8000)
+	chunk := captureRate.N(time.Millisecond * 10)
+	origin := time.Now()
+
+	for written := 0; ; written += chunk {
+		time.Sleep(time.Until(origin.Add(captureRate.D(written + chunk))))
+
+		data := make([]byte, chunk*2)
+
+		for i := 0; i < chunk; i++ {
+			at := origin.Add(captureRate.D(written + i))
+
+			if d := at.Sub(time.Unix(0, click)); d >= 0 && d < time.Millisecond {
+				binary.LittleEndian.PutUint16(data[i*2:], uint16(int16(30000)))
+			}
+		}
+
+		if _, err := os.Stdout.Write(data); err != nil {
+			os.Exit(0)
+		}
+	}
+}
+
+func TestMicrophoneRecorder_Record(t *testing.T) {
+	t.Run(
+		"should place the captured samples at their capture time",
+		func(t *testing.T) {
+			captureRate := beep.SampleRate(8000)
+			start := time.Now().Add(time.Millisecond * 600)
+			click := start.Add(time.Millisecond * 200)
+
+			command := []string{"env", "CALIBRATION_TEST_CLICK=" + strconv.FormatInt(click.UnixNano(), 10), os.Args[0], "-test.run=TestCaptureProcess"}
+			recorded, err := NewMicrophoneRecorder(command, 0).Record(start, time.Millisecond*500, captureRate)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(recorded) != captureRate.N(time.Millisecond*500) {
+				t.Fatalf("expected 500ms of samples, got %d", len(recorded))
+			}
+
+			peak := 0
+
+			for i, v := range recorded {
+				if v > recorded[peak] {
+					peak = i
+				}
+			}
+
+			if diff := captureRate.D(peak) - time.Millisecond*200; diff < -time.Millisecond*20 || diff > time.Millisecond*20 {
+				t.Errorf("expected the click after 200ms, got %s", captureRate.D(peak))
+			}
+		},
+	)
+
+	t.Run(
+		"should fail if the capture command ends early",
+		func(t *testing.T) {
+			_, err := NewMicrophoneRecorder([]string{"true"}, 0).Record(time.Now(), time.Second, rate)
+
+			if err == nil {
+				t.Error("expected an error")
+			}
+		},
+	)
+}
@@ -0,0 +1,128 @@
+package calibration
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// FindOffset searches the reference signal in the recording using cross-correlation.
+// It returns the offset of the best match in samples and its normalized correlation in the range [0, 1].
+func FindOffset(recording, reference []float64) (int, float64) {
+	if len(reference) == 0 || len(recording) < len(reference) {
+		return 0, 0
+	}
+
+	correlation := Correlate(recording, reference)
+
+	// prefix sums of the squared recording to normalize against the energy of each window
+	energy := make([]float64, len(recording)+1)
+
+	for i, v := range recording {
+		energy[i+1] = energy[i] + v*v
+	}
+
+	referenceEnergy := 0.0
+
+	for _, v := range reference {
+		referenceEnergy += v * v
+	}
+
+	offset := 0
+	best := 0.0
+
+	for lag := 0; lag <= len(recording)-len(reference); lag++ {
+		windowEnergy := energy[lag+len(reference)] - energy[lag]
+
+		if windowEnergy <= 0 {
+			continue
+		}
+
+		score := correlation[lag] / math.Sqrt(windowEnergy*referenceEnergy)
+
+		if score > best {
+			best = score
+			offset = lag
+		}
+	}
+
+	return offset, best
+}
+
+// Correlate returns the cross-correlation of the recording with the reference for all non-negative lags.
+func Correlate(recording, reference []float64) []float64 {
+	size := 1
+
+	for size < len(recording)+len(reference) {
+		size <<= 1
+	}
+
+	x := make([]complex128, size)
+	y := make([]complex128, size)
+
+	for i, v := range recording {
+		x[i] = complex(v, 0)
+	}
+
+	for i, v := range reference {
+		y[i] = complex(v, 0)
+	}
+
+	fft(x, false)
+	fft(y, false)
+
+	for i := range x {
+		x[i] *= cmplx.Conj(y[i])
+	}
+
+	fft(x, true)
+
+	correlation := make([]float64, len(recording))
+
+	for i := range correlation {
+		correlation[i] = real(x[i]) / float64(size)
+	}
+
+	return correlation
+}
+
+// fft is an in-place iterative radix-2 fast fourier transform, the length of values must be a power of two.
+func fft(values []complex128, inverse bool) {
+	n := len(values)
+
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+
+		j ^= bit
+
+		if i < j {
+			values[i], values[j] = values[j], values[i]
+		}
+	}
+
+	sign := -1.0
+
+	if inverse {
+		sign = 1.0
+	}
+
+	for length := 2; length <= n; length <<= 1 {
+		angle := sign * 2 * math.Pi / float64(length)
+		step := cmplx.Rect(1, angle)
+
+		for start := 0; start < n; start += length {
+			w := complex(1, 0)
+
+			for k := 0; k < length/2; k++ {
+				u := values[start+k]
+				v := values[start+k+length/2] * w
+				values[start+k] = u + v
+				values[start+k+length/2] = u - v
+				w *= step
+			}
+		}
+	}
+}
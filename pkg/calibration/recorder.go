@@ -0,0 +1,182 @@
+package calibration
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/wav"
+	"github.com/pkg/errors"
+)
+
+// Recorder captures the sound in the room, e.g. from a microphone.
+type Recorder interface {
+	// Record returns mono samples at the given rate, the first sample was captured at start.
+	Record(start time.Time, duration time.Duration, rate beep.SampleRate) ([]float64, error)
+}
+
+// WavRecorder replays a WAV file as recording, the file is treated as if it was captured starting at the requested time.
+// It is meant for testing without a microphone.
+type WavRecorder struct {
+	path string
+}
+
+func NewWavRecorder(path string) *WavRecorder {
+	return &WavRecorder{path: path}
+}
+
+func (r *WavRecorder) Record(start time.Time, duration time.Duration, rate beep.SampleRate) ([]float64, error) {
+	file, err := os.Open(r.path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	stream, format, err := wav.Decode(file)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "error decoding recording")
+	}
+
+	var streamer beep.Streamer = stream
+
+	if format.SampleRate != rate {
+		streamer = beep.Resample(3, format.SampleRate, rate, stream)
+	}
+
+	// wait until the recording window is over, like a real recording would
+	time.Sleep(time.Until(start.Add(duration)))
+
+	return Mono(streamer, rate.N(duration)), nil
+}
+
+// DefaultMicrophoneCommand records the default capture device of ALSA.
+var DefaultMicrophoneCommand = []string{"arecord", "-q", "-t", "raw", "-f", "S16_LE", "-c", "1", "-r", "{rate}"}
+
+// microphoneLead is the time the capture command is started before the recording, so it runs once the signal starts.
+const microphoneLead = time.Millisecond * 500
+
+// MicrophoneRecorder records the room with a capture command that writes raw mono samples, signed 16 bit little
+// endian, to its output, e.g. arecord. The placeholder {rate} in the arguments is replaced by the sample rate.
+// The capture time of the samples is derived from the time they are read, the latency is the time between
+// capturing and reading a sample, e.g. the buffer of the capture device.
+type MicrophoneRecorder struct {
+	command []string
+	latency time.Duration
+}
+
+func NewMicrophoneRecorder(command []string, latency time.Duration) *MicrophoneRecorder {
+	return &MicrophoneRecorder{command: command, latency: latency}
+}
+
+func (r *MicrophoneRecorder) Record(start time.Time, duration time.Duration, rate beep.SampleRate) ([]float64, error) {
+	if len(r.command) == 0 {
+		return nil, errors.New("no capture command")
+	}
+
+	args := make([]string, len(r.command)-1)
+
+	for i, arg := range r.command[1:] {
+		args[i] = strings.ReplaceAll(arg, "{rate}", strconv.Itoa(int(rate)))
+	}
+
+	time.Sleep(time.Until(start.Add(-microphoneLead)))
+
+	cmd := exec.Command(r.command[0], args...)
+	output, err := cmd.StdoutPipe()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Wrap(err, "error starting the capture command")
+	}
+
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	end := start.Add(duration)
+	buffer := make([]byte, 4096)
+	var captured []float64
+	var pending []byte
+
+	// the capture time of the first sample, the earliest estimate is the best as reads are only ever late
+	var origin time.Time
+
+	for origin.IsZero() || origin.Add(rate.D(len(captured))).Before(end) {
+		n, err := output.Read(buffer)
+
+		if n > 0 {
+			read := time.Now().Add(-r.latency)
+			pending = append(pending, buffer[:n]...)
+
+			for len(pending) >= 2 {
+				captured = append(captured, float64(int16(binary.LittleEndian.Uint16(pending)))/32768)
+				pending = pending[2:]
+			}
+
+			if estimate := read.Add(-rate.D(len(captured))); origin.IsZero() || estimate.Before(origin) {
+				origin = estimate
+			}
+		}
+
+		if err == io.EOF {
+			return nil, errors.New("capture command ended before the recording")
+		}
+
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading the capture command")
+		}
+	}
+
+	return window(captured, rate.N(start.Sub(origin)), rate.N(duration)), nil
+}
+
+// window returns n samples of the captured samples from the offset, samples before the capture are silent.
+func window(captured []float64, offset int, n int) []float64 {
+	samples := make([]float64, n)
+
+	for i := range samples {
+		if j := offset + i; j >= 0 && j < len(captured) {
+			samples[i] = captured[j]
+		}
+	}
+
+	return samples
+}
+
+// Mono reads up to n samples from the streamer and downmixes them to mono.
+func Mono(streamer beep.Streamer, n int) []float64 {
+	samples := make([]float64, 0, n)
+	buffer := make([][2]float64, 512)
+
+	for len(samples) < n {
+		size := len(buffer)
+
+		if n-len(samples) < size {
+			size = n - len(samples)
+		}
+
+		read, ok := streamer.Stream(buffer[:size])
+
+		for i := 0; i < read; i++ {
+			samples = append(samples, (buffer[i][0]+buffer[i][1])/2)
+		}
+
+		if !ok {
+			break
+		}
+	}
+
+	return samples
+}
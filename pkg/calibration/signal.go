@@ -0,0 +1,66 @@
+package calibration
+
+import (
+	"math"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+const (
+	chirpDuration = time.Millisecond * 200
+	chirpGap      = time.Millisecond * 300
+	chirpCount    = 3
+	chirpFrom     = 500.0
+	chirpTo       = 8000.0
+	chirpFade     = time.Millisecond * 5
+	amplitude     = 0.8
+)
+
+// Signal returns the calibration signal: a sequence of linear sine sweeps separated by silence.
+// The signal is deterministic, so the recording side can generate the same reference on its own.
+func Signal(rate beep.SampleRate) []float64 {
+	chirp := Chirp(rate, chirpDuration, chirpFrom, chirpTo)
+	gap := rate.N(chirpGap)
+
+	signal := make([]float64, 0, chirpCount*(len(chirp)+gap))
+
+	for i := 0; i < chirpCount; i++ {
+		if i > 0 {
+			signal = append(signal, make([]float64, gap)...)
+		}
+
+		signal = append(signal, chirp...)
+	}
+
+	return signal
+}
+
+// SignalDuration returns the duration of the calibration signal.
+func SignalDuration() time.Duration {
+	return chirpCount*chirpDuration + (chirpCount-1)*chirpGap
+}
+
+// Chirp returns a linear sine sweep from one frequency to another, faded in and out to avoid clicks.
+func Chirp(rate beep.SampleRate, duration time.Duration, from, to float64) []float64 {
+	n := rate.N(duration)
+	fade := rate.N(chirpFade)
+	seconds := duration.Seconds()
+	chirp := make([]float64, n)
+
+	for i := range chirp {
+		t := float64(i) / float64(rate)
+		phase := 2 * math.Pi * (from*t + (to-from)*t*t/(2*seconds))
+		gain := amplitude
+
+		if i < fade {
+			gain *= float64(i) / float64(fade)
+		} else if n-i < fade {
+			gain *= float64(n-i) / float64(fade)
+		}
+
+		chirp[i] = gain * math.Sin(phase)
+	}
+
+	return chirp
+}
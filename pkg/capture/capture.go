@@ -0,0 +1,219 @@
+// Package capture records audio to WAV together with the time each block was scheduled for,
+// so recordings of the server and the clients can be compared sample by sample.
+package capture
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+
+	"network-audio/pkg/wavx"
+)
+
+// Capture writes blocks of samples to a WAV file and optionally a CSV timing file next to it.
+// Every row of the timing file holds the first frame of a block, its time in unix nanoseconds and its amount of frames.
+// A recording that outgrows a WAV file continues in the next part, see PartPath, each part with its own timing file.
+type Capture struct {
+	path       string
+	format     beep.Format
+	withTiming bool
+
+	wav    *wavx.Writer
+	timing *bufferedFile
+	frames int
+
+	// the current part and its frames, a block that does not fit into the part starts the next one
+	part       int
+	partFrames int64
+	maxFrames  int64
+
+	lock *sync.Mutex
+}
+
+// Create creates the WAV file at path, with timing enabled the timing is written to path + ".csv".
+func Create(path string, format beep.Format, timing bool) (*Capture, error) {
+	c := &Capture{
+		path:       path,
+		format:     format,
+		withTiming: timing,
+		maxFrames:  wavx.MaxFrames(format),
+		lock:       &sync.Mutex{},
+	}
+
+	err := c.open(1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// PartPath returns the path of a part of the recording, the first part is at the path, e.g. the second part of
+// rec.wav is rec-2.wav.
+func PartPath(path string, part int) string {
+	if part <= 1 {
+		return path
+	}
+
+	extension := filepath.Ext(path)
+
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(path, extension), part, extension)
+}
+
+// open creates the files of the part.
+func (c *Capture) open(part int) error {
+	path := PartPath(c.path, part)
+	file, err := createBuffered(path)
+
+	if err != nil {
+		return err
+	}
+
+	wav, err := wavx.NewWriter(file, c.format)
+
+	if err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	var timing *bufferedFile
+
+	if c.withTiming {
+		timing, err = createBuffered(TimingPath(path))
+
+		if err == nil {
+			_, err = timing.WriteString("frame,time,frames\n")
+		}
+
+		if err != nil {
+			_ = wav.Close()
+
+			return err
+		}
+	}
+
+	c.wav = wav
+	c.timing = timing
+	c.part = part
+	c.partFrames = 0
+
+	return nil
+}
+
+// rotate closes the current part and continues in the next one.
+func (c *Capture) rotate() error {
+	err := c.close()
+
+	if err != nil {
+		return err
+	}
+
+	return c.open(c.part + 1)
+}
+
+// TimingPath returns the path of the timing file of the recording.
+func TimingPath(path string) string {
+	return path + ".csv"
+}
+
+func (c *Capture) Format() beep.Format {
+	return c.format
+}
+
+// Write appends the samples, the first of them is played at the given time.
+func (c *Capture) Write(samples [][2]float64, start time.Time) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.partFrames+int64(len(samples)) > c.maxFrames {
+		err := c.rotate()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.timing != nil {
+		_, err := fmt.Fprintf(c.timing, "%d,%d,%d\n", c.partFrames, start.UnixNano(), len(samples))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	c.frames += len(samples)
+	c.partFrames += int64(len(samples))
+
+	return c.wav.Write(samples)
+}
+
+// Frames returns the amount of frames recorded so far, in all parts.
+func (c *Capture) Frames() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.frames
+}
+
+func (c *Capture) Close() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.close()
+}
+
+func (c *Capture) close() error {
+	err := c.wav.Close()
+
+	if c.timing != nil {
+		if timingErr := c.timing.Close(); err == nil {
+			err = timingErr
+		}
+	}
+
+	return err
+}
+
+// bufferedFile keeps writes from the audio callback off the disk, it is flushed before seeking and closing.
+type bufferedFile struct {
+	*bufio.Writer
+	file *os.File
+}
+
+func createBuffered(path string) (*bufferedFile, error) {
+	file, err := os.Create(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &bufferedFile{Writer: bufio.NewWriterSize(file, 64*1024), file: file}, nil
+}
+
+func (f *bufferedFile) Seek(offset int64, whence int) (int64, error) {
+	err := f.Flush()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return f.file.Seek(offset, whence)
+}
+
+func (f *bufferedFile) Close() error {
+	err := f.Flush()
+
+	if closeErr := f.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
@@ -0,0 +1,78 @@
+package capture
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+func TestCapture_Write(t *testing.T) {
+	t.Run(
+		"should continue in the next part when a wav file is full",
+		func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rec.wav")
+			format := beep.Format{SampleRate: 1000, NumChannels: 2, Precision: 2}
+			c, err := Create(path, format, true)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c.maxFrames = 25
+			start := time.Unix(100, 0)
+
+			for i := 0; i < 3; i++ {
+				if err := c.Write(make([][2]float64, 10), start.Add(time.Duration(i)*time.Millisecond*10)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := c.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if c.Frames() != 30 {
+				t.Errorf("expected 30 frames, got %d", c.Frames())
+			}
+
+			for part, frames := range map[int]int{1: 20, 2: 10} {
+				info, err := os.Stat(PartPath(path, part))
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if size := int(info.Size()) - 44; size != frames*format.Width() {
+					t.Errorf("expected %d frames in part %d, got %d bytes", frames, part, size)
+				}
+			}
+
+			timing, err := os.ReadFile(TimingPath(PartPath(path, 2)))
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.HasPrefix(string(timing), "frame,time,frames\n0,") {
+				t.Errorf("expected the timing of the part to start at frame 0, got %q", timing)
+			}
+		},
+	)
+
+	t.Run(
+		"should number the parts before the extension",
+		func(t *testing.T) {
+			if p := PartPath("/tmp/rec.wav", 1); p != "/tmp/rec.wav" {
+				t.Errorf("unexpected first part %s", p)
+			}
+
+			if p := PartPath("/tmp/rec.wav", 3); p != "/tmp/rec-3.wav" {
+				t.Errorf("unexpected third part %s", p)
+			}
+		},
+	)
+}
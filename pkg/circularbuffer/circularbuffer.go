@@ -1,7 +1,9 @@
 package circularbuffer
 
 import (
+	"context"
 	"sync"
+	"time"
 )
 
 // Queue is a thread-safe circular buffer.
@@ -9,27 +11,39 @@ type Queue struct {
 	buffer  []any
 	maxSize int
 
-	// Ensures that Enqueue waits until the buffer is not full
-	// and Dequeue waits until the buffer is not empty
-	cond *sync.Cond
+	// Enqueue drops the oldest item instead of waiting if the buffer is full
+	overwrite bool
 
-	readerMutex    sync.RWMutex
+	lock           *sync.Mutex
 	readerPosition int
+	size           int
 
-	writerMutex    sync.RWMutex
-	writerPosition int
+	// Closed whenever items are added or removed, so waiting calls can select on it together with a context.
+	// It is only created if a call waits.
+	changed chan struct{}
+}
+
+type Option func(*Queue)
 
-	fullMutex sync.RWMutex
-	isFull    bool
+// WithOverwrite lets Enqueue replace the oldest item if the queue is full instead of waiting, e.g. for live data.
+func WithOverwrite() Option {
+	return func(queue *Queue) {
+		queue.overwrite = true
+	}
 }
 
 // New creates a new Queue with the given maximum size.
-func New(maxSize int) *Queue {
+func New(maxSize int, options ...Option) *Queue {
 	if maxSize < 1 {
 		panic("Invalid maxSize, should be at least 1")
 	}
 
-	queue := &Queue{maxSize: maxSize}
+	queue := &Queue{maxSize: maxSize, lock: &sync.Mutex{}}
+
+	for _, option := range options {
+		option(queue)
+	}
+
 	queue.Clear()
 
 	return queue
@@ -37,69 +51,205 @@ func New(maxSize int) *Queue {
 
 // Enqueue adds an item to the end of the queue. If the queue is full, the call blocks until an item is dequeued.
 func (queue *Queue) Enqueue(value any) {
-	queue.waitNotFull()
+	_ = queue.EnqueueContext(context.Background(), value)
+}
+
+// EnqueueContext adds an item to the end of the queue, it returns the error of the context if it is done before there is space.
+func (queue *Queue) EnqueueContext(ctx context.Context, value any) error {
+	for {
+		queue.lock.Lock()
 
-	queue.writerMutex.Lock()
+		if queue.size < queue.maxSize || queue.overwrite {
+			queue.push(value)
+			queue.lock.Unlock()
 
-	queue.buffer[queue.writerPosition] = value
+			return nil
+		}
 
-	// Increment writerPosition
-	queue.writerPosition = queue.writerPosition + 1
+		changed := queue.wait()
+		queue.lock.Unlock()
 
-	// If the writerPosition is at the end of the buffer, set it to the beginning
-	if queue.writerPosition >= queue.maxSize {
-		queue.writerPosition = 0
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
+}
 
-	queue.readerMutex.RLock()
-	if queue.writerPosition == queue.readerPosition {
-		queue.fullMutex.Lock()
-		queue.isFull = true
-		queue.fullMutex.Unlock()
+// EnqueueTimeout adds an item to the end of the queue, it returns context.DeadlineExceeded if there is no space within the timeout.
+func (queue *Queue) EnqueueTimeout(value any, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return queue.EnqueueContext(ctx, value)
+}
+
+// TryEnqueue adds an item to the end of the queue if there is space, it never blocks.
+func (queue *Queue) TryEnqueue(value any) bool {
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+
+	if queue.size == queue.maxSize && !queue.overwrite {
+		return false
 	}
-	queue.readerMutex.RUnlock()
 
-	queue.writerMutex.Unlock()
+	queue.push(value)
+
+	return true
+}
+
+// EnqueueBulk adds all items to the end of the queue, waiting for space as often as needed.
+func (queue *Queue) EnqueueBulk(values []any) {
+	for len(values) > 0 {
+		n := queue.TryEnqueueBulk(values)
+		values = values[n:]
 
-	queue.cond.Broadcast()
+		if len(values) > 0 && n == 0 {
+			queue.Enqueue(values[0])
+			values = values[1:]
+		}
+	}
+}
+
+// TryEnqueueBulk adds as many items as there is space for and returns their amount, it never blocks.
+// With overwrite, all items are added and the oldest ones are dropped.
+func (queue *Queue) TryEnqueueBulk(values []any) int {
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+
+	n := len(values)
+
+	if !queue.overwrite && queue.maxSize-queue.size < n {
+		n = queue.maxSize - queue.size
+	}
+
+	for _, value := range values[:n] {
+		queue.push(value)
+	}
+
+	return n
 }
 
 // Dequeue removes an item from the beginning of the queue. If the queue is empty, the call blocks until an item is enqueued.
 func (queue *Queue) Dequeue() any {
-	queue.waitNotEmpty()
+	value, _ := queue.DequeueContext(context.Background())
 
-	queue.readerMutex.Lock()
+	return value
+}
 
-	value := queue.buffer[queue.readerPosition]
+// DequeueContext removes an item from the beginning of the queue, it returns the error of the context if it is done before an item is available.
+func (queue *Queue) DequeueContext(ctx context.Context) (any, error) {
+	for {
+		queue.lock.Lock()
 
-	if value != nil {
-		queue.buffer[queue.readerPosition] = nil
-		queue.readerPosition = queue.readerPosition + 1
+		if queue.size > 0 {
+			value := queue.pop()
+			queue.lock.Unlock()
 
-		if queue.readerPosition >= queue.maxSize {
-			queue.readerPosition = 0
+			return value, nil
 		}
 
-		queue.fullMutex.Lock()
-		queue.isFull = false
-		queue.fullMutex.Unlock()
+		changed := queue.wait()
+		queue.lock.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
 	}
+}
 
-	queue.readerMutex.Unlock()
+// DequeueTimeout removes an item from the beginning of the queue, it returns context.DeadlineExceeded if no item is available within the timeout.
+func (queue *Queue) DequeueTimeout(timeout time.Duration) (any, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
-	queue.cond.Broadcast()
+	return queue.DequeueContext(ctx)
+}
 
-	return value
+// TryDequeue removes an item from the beginning of the queue if there is one, it never blocks.
+func (queue *Queue) TryDequeue() (any, bool) {
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+
+	if queue.size == 0 {
+		return nil, false
+	}
+
+	return queue.pop(), true
+}
+
+// DequeueBulk waits until the queue is not empty and removes up to len(items) items into items, it returns their amount.
+func (queue *Queue) DequeueBulk(items []any) int {
+	if len(items) == 0 {
+		return 0
+	}
+
+	items[0] = queue.Dequeue()
+
+	return 1 + queue.TryDequeueBulk(items[1:])
+}
+
+// TryDequeueBulk removes up to len(items) items into items and returns their amount, it never blocks.
+func (queue *Queue) TryDequeueBulk(items []any) int {
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+
+	n := len(items)
+
+	if queue.size < n {
+		n = queue.size
+	}
+
+	for i := 0; i < n; i++ {
+		items[i] = queue.pop()
+	}
+
+	return n
 }
 
 // Peek returns the item at the beginning of the queue without removing it. If the queue is empty, the call blocks until an item is enqueued.
 func (queue *Queue) Peek() any {
-	queue.waitNotEmpty()
+	value, _ := queue.PeekContext(context.Background())
+
+	return value
+}
+
+// PeekContext returns the item at the beginning of the queue without removing it, it returns the error of the context if it is done before an item is available.
+func (queue *Queue) PeekContext(ctx context.Context) (any, error) {
+	for {
+		queue.lock.Lock()
+
+		if queue.size > 0 {
+			value := queue.buffer[queue.readerPosition]
+			queue.lock.Unlock()
+
+			return value, nil
+		}
+
+		changed := queue.wait()
+		queue.lock.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
 
-	queue.writerMutex.Lock()
-	defer queue.writerMutex.Unlock()
+// TryPeek returns the item at the beginning of the queue without removing it if there is one, it never blocks.
+func (queue *Queue) TryPeek() (any, bool) {
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+
+	if queue.size == 0 {
+		return nil, false
+	}
 
-	return queue.buffer[queue.readerPosition]
+	return queue.buffer[queue.readerPosition], true
 }
 
 func (queue *Queue) Empty() bool {
@@ -107,62 +257,65 @@ func (queue *Queue) Empty() bool {
 }
 
 func (queue *Queue) Full() bool {
-	queue.fullMutex.RLock()
-	defer queue.fullMutex.RUnlock()
-
-	return queue.isFull
+	return queue.Size() == queue.maxSize
 }
 
 func (queue *Queue) Size() int {
-	queue.readerMutex.RLock()
-	defer queue.readerMutex.RUnlock()
-	queue.writerMutex.RLock()
-	defer queue.writerMutex.RUnlock()
-
-	if queue.writerPosition < queue.readerPosition {
-		return queue.maxSize - queue.readerPosition + queue.writerPosition
-	} else if queue.writerPosition == queue.readerPosition {
-		if queue.Full() {
-			return queue.maxSize
-		} else {
-			return 0
-		}
-	}
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
 
-	return queue.writerPosition - queue.readerPosition
+	return queue.size
 }
 
 func (queue *Queue) Clear() {
-	queue.readerMutex.Lock()
-	defer queue.readerMutex.Unlock()
-	queue.writerMutex.Lock()
-	defer queue.writerMutex.Unlock()
-	queue.fullMutex.Lock()
-	defer queue.fullMutex.Unlock()
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
 
 	queue.buffer = make([]any, queue.maxSize, queue.maxSize)
-	queue.cond = sync.NewCond(&sync.Mutex{})
 	queue.readerPosition = 0
-	queue.writerPosition = 0
-	queue.isFull = false
-}
+	queue.size = 0
 
-func (queue *Queue) waitNotEmpty() {
-	queue.cond.L.Lock()
+	queue.notify()
+}
 
-	for queue.Empty() {
-		queue.cond.Wait()
+// push adds the value while the lock is held, the oldest item is dropped if the queue is full.
+func (queue *Queue) push(value any) {
+	if queue.size == queue.maxSize {
+		queue.pop()
 	}
 
-	queue.cond.L.Unlock()
+	queue.buffer[(queue.readerPosition+queue.size)%queue.maxSize] = value
+	queue.size++
+
+	queue.notify()
 }
 
-func (queue *Queue) waitNotFull() {
-	queue.cond.L.Lock()
+// pop removes the first value while the lock is held.
+func (queue *Queue) pop() any {
+	value := queue.buffer[queue.readerPosition]
+
+	queue.buffer[queue.readerPosition] = nil
+	queue.readerPosition = (queue.readerPosition + 1) % queue.maxSize
+	queue.size--
+
+	queue.notify()
+
+	return value
+}
 
-	for queue.Full() {
-		queue.cond.Wait()
+// wait returns the channel that is closed on the next change while the lock is held.
+func (queue *Queue) wait() chan struct{} {
+	if queue.changed == nil {
+		queue.changed = make(chan struct{})
 	}
 
-	queue.cond.L.Unlock()
+	return queue.changed
+}
+
+// notify wakes up all waiting calls while the lock is held.
+func (queue *Queue) notify() {
+	if queue.changed != nil {
+		close(queue.changed)
+		queue.changed = nil
+	}
 }
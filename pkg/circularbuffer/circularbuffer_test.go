@@ -1,6 +1,7 @@
 package circularbuffer
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -317,3 +318,225 @@ func TestQueue_Size(t *testing.T) {
 		},
 	)
 }
+
+func TestQueue_TryEnqueue(t *testing.T) {
+	t.Run(
+		"should not block if the queue is full",
+		func(t *testing.T) {
+			q := New(1)
+
+			if !q.TryEnqueue(1) {
+				t.Fatal("queue did not enqueue")
+			}
+
+			if q.TryEnqueue(2) {
+				t.Fatal("queue enqueued into a full queue")
+			}
+
+			if v := q.Dequeue(); v != 1 {
+				t.Fatal("dequeue value is not 1")
+			}
+		},
+	)
+
+	t.Run(
+		"should overwrite the oldest element with the overwrite policy",
+		func(t *testing.T) {
+			q := New(2, WithOverwrite())
+
+			q.Enqueue(1)
+			q.Enqueue(2)
+
+			if !q.TryEnqueue(3) {
+				t.Fatal("queue did not enqueue")
+			}
+
+			q.Enqueue(4)
+
+			if s := q.Size(); s != 2 {
+				t.Fatal("queue size is not 2")
+			}
+
+			if v := q.Dequeue(); v != 3 {
+				t.Fatal("dequeue value is not 3")
+			}
+
+			if v := q.Dequeue(); v != 4 {
+				t.Fatal("dequeue value is not 4")
+			}
+		},
+	)
+}
+
+func TestQueue_TryDequeue(t *testing.T) {
+	t.Run(
+		"should not block if the queue is empty",
+		func(t *testing.T) {
+			q := New(2)
+
+			if _, ok := q.TryDequeue(); ok {
+				t.Fatal("queue dequeued from an empty queue")
+			}
+
+			if _, ok := q.TryPeek(); ok {
+				t.Fatal("queue peeked into an empty queue")
+			}
+
+			q.Enqueue(1)
+
+			if v, ok := q.TryPeek(); !ok || v != 1 {
+				t.Fatal("peek value is not 1")
+			}
+
+			if v, ok := q.TryDequeue(); !ok || v != 1 {
+				t.Fatal("dequeue value is not 1")
+			}
+
+			if !q.Empty() {
+				t.Fatal("queue is not empty")
+			}
+		},
+	)
+}
+
+func TestQueue_Timeout(t *testing.T) {
+	t.Run(
+		"should stop waiting for an element after the timeout",
+		func(t *testing.T) {
+			q := New(1)
+			start := time.Now()
+
+			_, err := q.DequeueTimeout(time.Millisecond * 50)
+
+			if err != context.DeadlineExceeded {
+				t.Fatalf("dequeue error is %v", err)
+			}
+
+			if time.Since(start) < time.Millisecond*50 {
+				t.Fatal("queue did not wait")
+			}
+		},
+	)
+
+	t.Run(
+		"should stop waiting for space after the timeout",
+		func(t *testing.T) {
+			q := New(1)
+
+			q.Enqueue(1)
+
+			if err := q.EnqueueTimeout(2, time.Millisecond*50); err != context.DeadlineExceeded {
+				t.Fatalf("enqueue error is %v", err)
+			}
+
+			if s := q.Size(); s != 1 {
+				t.Fatal("queue size is not 1")
+			}
+		},
+	)
+
+	t.Run(
+		"should stop waiting if the context is canceled",
+		func(t *testing.T) {
+			q := New(1)
+			ctx, cancel := context.WithCancel(context.Background())
+
+			go func() {
+				time.Sleep(time.Millisecond * 50)
+				cancel()
+			}()
+
+			if _, err := q.PeekContext(ctx); err != context.Canceled {
+				t.Fatalf("peek error is %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"should return the element if it arrives before the timeout",
+		func(t *testing.T) {
+			q := New(1)
+
+			go func() {
+				time.Sleep(time.Millisecond * 50)
+				q.Enqueue(1)
+			}()
+
+			v, err := q.DequeueTimeout(time.Second)
+
+			if err != nil || v != 1 {
+				t.Fatalf("dequeue returned %v, %v", v, err)
+			}
+		},
+	)
+}
+
+func TestQueue_Bulk(t *testing.T) {
+	t.Run(
+		"should enqueue and dequeue as many elements as possible",
+		func(t *testing.T) {
+			q := New(3)
+			items := make([]any, 4)
+
+			if n := q.TryEnqueueBulk([]any{1, 2, 3, 4}); n != 3 {
+				t.Fatalf("enqueued %d elements, not 3", n)
+			}
+
+			if n := q.TryDequeueBulk(items[:2]); n != 2 || items[0] != 1 || items[1] != 2 {
+				t.Fatalf("dequeued %v", items[:n])
+			}
+
+			if n := q.TryDequeueBulk(items); n != 1 || items[0] != 3 {
+				t.Fatalf("dequeued %v", items[:n])
+			}
+		},
+	)
+
+	t.Run(
+		"should wait until all elements are enqueued",
+		func(t *testing.T) {
+			q := New(2)
+			wg := &sync.WaitGroup{}
+			var values []any
+
+			wg.Add(1)
+
+			go func() {
+				items := make([]any, 2)
+
+				for len(values) < 5 {
+					n := q.DequeueBulk(items)
+					values = append(values, items[:n]...)
+				}
+
+				wg.Done()
+			}()
+
+			q.EnqueueBulk([]any{1, 2, 3, 4, 5})
+
+			wg.Wait()
+
+			for i, v := range values {
+				if v != i+1 {
+					t.Fatalf("dequeued %v", values)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"should keep the newest elements with the overwrite policy",
+		func(t *testing.T) {
+			q := New(2, WithOverwrite())
+			items := make([]any, 2)
+
+			if n := q.TryEnqueueBulk([]any{1, 2, 3}); n != 3 {
+				t.Fatalf("enqueued %d elements, not 3", n)
+			}
+
+			if n := q.DequeueBulk(items); n != 2 || items[0] != 2 || items[1] != 3 {
+				t.Fatalf("dequeued %v", items[:n])
+			}
+		},
+	)
+}
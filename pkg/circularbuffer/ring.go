@@ -0,0 +1,158 @@
+package circularbuffer
+
+import (
+	"sync/atomic"
+)
+
+// Ring is a lock-free circular buffer for exactly one producer and one consumer goroutine.
+// Items are stored by value, so reads and writes do not allocate. None of the calls block,
+// they return how many items were transferred instead.
+type Ring[T any] struct {
+	// positions grow monotonically, the index in the buffer is the position masked by the capacity.
+	// They are kept on separate cache lines, so producer and consumer do not slow each other down.
+	readPosition uint64
+	_            [56]byte
+
+	writePosition uint64
+	_             [56]byte
+
+	buffer []T
+	mask   uint64
+}
+
+// NewRing creates a ring that holds at least capacity items, the capacity is rounded up to a power of two.
+func NewRing[T any](capacity int) *Ring[T] {
+	if capacity < 1 {
+		panic("Invalid capacity, should be at least 1")
+	}
+
+	size := 1
+
+	for size < capacity {
+		size <<= 1
+	}
+
+	return &Ring[T]{
+		buffer: make([]T, size),
+		mask:   uint64(size - 1),
+	}
+}
+
+func (r *Ring[T]) Cap() int {
+	return len(r.buffer)
+}
+
+// Len returns the amount of items that can be read.
+func (r *Ring[T]) Len() int {
+	return int(atomic.LoadUint64(&r.writePosition) - atomic.LoadUint64(&r.readPosition))
+}
+
+// Free returns the amount of items that can be written.
+func (r *Ring[T]) Free() int {
+	return r.Cap() - r.Len()
+}
+
+// Write appends as many items as fit and returns their amount. It must only be called by the producer.
+func (r *Ring[T]) Write(items []T) int {
+	write := atomic.LoadUint64(&r.writePosition)
+	read := atomic.LoadUint64(&r.readPosition)
+
+	n := len(r.buffer) - int(write-read)
+
+	if n > len(items) {
+		n = len(items)
+	}
+
+	if n <= 0 {
+		return 0
+	}
+
+	start := int(write & r.mask)
+	copied := copy(r.buffer[start:], items[:n])
+	copy(r.buffer, items[copied:n])
+
+	atomic.StoreUint64(&r.writePosition, write+uint64(n))
+
+	return n
+}
+
+// Push appends a single item, it returns false if the ring is full. It must only be called by the producer.
+func (r *Ring[T]) Push(item T) bool {
+	write := atomic.LoadUint64(&r.writePosition)
+
+	if int(write-atomic.LoadUint64(&r.readPosition)) == len(r.buffer) {
+		return false
+	}
+
+	r.buffer[write&r.mask] = item
+	atomic.StoreUint64(&r.writePosition, write+1)
+
+	return true
+}
+
+// Peek copies the oldest items into items without removing them and returns their amount.
+// It must only be called by the consumer.
+func (r *Ring[T]) Peek(items []T) int {
+	read := atomic.LoadUint64(&r.readPosition)
+	write := atomic.LoadUint64(&r.writePosition)
+
+	n := int(write - read)
+
+	if n > len(items) {
+		n = len(items)
+	}
+
+	start := int(read & r.mask)
+	copied := copy(items[:n], r.buffer[start:])
+	copy(items[copied:n], r.buffer)
+
+	return n
+}
+
+// Read removes the oldest items into items and returns their amount. It must only be called by the consumer.
+func (r *Ring[T]) Read(items []T) int {
+	n := r.Peek(items)
+
+	r.Discard(n)
+
+	return n
+}
+
+// Pop removes the oldest item, it returns false if the ring is empty. It must only be called by the consumer.
+func (r *Ring[T]) Pop() (T, bool) {
+	var item T
+
+	read := atomic.LoadUint64(&r.readPosition)
+
+	if read == atomic.LoadUint64(&r.writePosition) {
+		return item, false
+	}
+
+	item = r.buffer[read&r.mask]
+	atomic.StoreUint64(&r.readPosition, read+1)
+
+	return item, true
+}
+
+// Discard removes up to n of the oldest items and returns their amount. It must only be called by the consumer.
+func (r *Ring[T]) Discard(n int) int {
+	read := atomic.LoadUint64(&r.readPosition)
+	available := int(atomic.LoadUint64(&r.writePosition) - read)
+
+	if n > available {
+		n = available
+	}
+
+	if n <= 0 {
+		return 0
+	}
+
+	atomic.StoreUint64(&r.readPosition, read+uint64(n))
+
+	return n
+}
+
+// Clear removes all items. It must only be called by the consumer.
+func (r *Ring[T]) Clear() {
+	atomic.StoreUint64(&r.readPosition, atomic.LoadUint64(&r.writePosition))
+}
@@ -0,0 +1,205 @@
+package circularbuffer
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestRing_ReadWrite(t *testing.T) {
+	t.Run(
+		"should round the capacity up to a power of two",
+		func(t *testing.T) {
+			r := NewRing[int](5)
+
+			if c := r.Cap(); c != 8 {
+				t.Fatalf("ring capacity is %d, not 8", c)
+			}
+		},
+	)
+
+	t.Run(
+		"should read the items in the written order across the end of the buffer",
+		func(t *testing.T) {
+			r := NewRing[int](4)
+			items := make([]int, 4)
+
+			if n := r.Write([]int{1, 2, 3}); n != 3 {
+				t.Fatalf("wrote %d items, not 3", n)
+			}
+
+			if n := r.Read(items[:2]); n != 2 || items[0] != 1 || items[1] != 2 {
+				t.Fatalf("read %v", items[:n])
+			}
+
+			// only 3 of the 4 items fit
+			if n := r.Write([]int{4, 5, 6, 7}); n != 3 {
+				t.Fatalf("wrote %d items, not 3", n)
+			}
+
+			if s := r.Len(); s != 4 {
+				t.Fatalf("ring length is %d, not 4", s)
+			}
+
+			if n := r.Peek(items); n != 4 || items[0] != 3 || items[3] != 6 {
+				t.Fatalf("peeked %v", items[:n])
+			}
+
+			if n := r.Read(items); n != 4 || items[0] != 3 || items[3] != 6 {
+				t.Fatalf("read %v", items[:n])
+			}
+
+			if n := r.Read(items); n != 0 {
+				t.Fatalf("read %d items from an empty ring", n)
+			}
+		},
+	)
+
+	t.Run(
+		"should push, pop and discard single items",
+		func(t *testing.T) {
+			r := NewRing[int](2)
+
+			if !r.Push(1) || !r.Push(2) || r.Push(3) {
+				t.Fatal("push did not respect the capacity")
+			}
+
+			if v, ok := r.Pop(); !ok || v != 1 {
+				t.Fatalf("pop returned %d, %v", v, ok)
+			}
+
+			if n := r.Discard(5); n != 1 {
+				t.Fatalf("discarded %d items, not 1", n)
+			}
+
+			if _, ok := r.Pop(); ok {
+				t.Fatal("pop returned an item from an empty ring")
+			}
+		},
+	)
+
+	t.Run(
+		"should transfer all items between a producer and a consumer",
+		func(t *testing.T) {
+			r := NewRing[int](64)
+			total := 100000
+			wg := &sync.WaitGroup{}
+
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				items := make([]int, 48)
+				next := 0
+
+				for next < total {
+					count := len(items)
+
+					if total-next < count {
+						count = total - next
+					}
+
+					for i := 0; i < count; i++ {
+						items[i] = next + i
+					}
+
+					n := r.Write(items[:count])
+					next += n
+
+					if n == 0 {
+						runtime.Gosched()
+					}
+				}
+			}()
+
+			items := make([]int, 32)
+			expected := 0
+
+			for expected < total {
+				n := r.Read(items)
+
+				if n == 0 {
+					runtime.Gosched()
+				}
+
+				for _, item := range items[:n] {
+					if item != expected {
+						t.Fatalf("read %d, expected %d", item, expected)
+					}
+
+					expected++
+				}
+			}
+
+			wg.Wait()
+		},
+	)
+}
+
+type benchmarkFrame struct {
+	data [8]float64
+	time int64
+}
+
+func BenchmarkQueue(b *testing.B) {
+	q := New(4096)
+	done := make(chan bool)
+
+	go func() {
+		for i := 0; i < b.N; i++ {
+			q.Dequeue()
+		}
+
+		done <- true
+	}()
+
+	for i := 0; i < b.N; i++ {
+		q.Enqueue(&benchmarkFrame{time: int64(i)})
+	}
+
+	<-done
+}
+
+func BenchmarkRing(b *testing.B) {
+	r := NewRing[benchmarkFrame](4096)
+	done := make(chan bool)
+
+	go func() {
+		items := make([]benchmarkFrame, 512)
+
+		for read := 0; read < b.N; {
+			n := r.Read(items)
+			read += n
+
+			if n == 0 {
+				runtime.Gosched()
+			}
+		}
+
+		done <- true
+	}()
+
+	items := make([]benchmarkFrame, 512)
+
+	for written := 0; written < b.N; {
+		count := len(items)
+
+		if b.N-written < count {
+			count = b.N - written
+		}
+
+		for i := range items[:count] {
+			items[i].time = int64(written + i)
+		}
+
+		n := r.Write(items[:count])
+		written += n
+
+		if n == 0 {
+			runtime.Gosched()
+		}
+	}
+
+	<-done
+}
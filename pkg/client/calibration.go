@@ -0,0 +1,55 @@
+package client
+
+import (
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/pkg/errors"
+
+	"network-audio/pkg/calibration"
+	"network-audio/pkg/messages"
+	"network-audio/pkg/timex"
+)
+
+// calibrate records the calibration signal of the target client and reports the measured delay to the server.
+func (c *Client) calibrate(m *messages.Calibration) {
+	result := &messages.CalibrationResult{
+		Target: m.Target,
+	}
+
+	delay, confidence, err := c.measure(m)
+
+	if err != nil {
+		c.logger.Warnf("calibration of %s failed: %s", m.Target, err)
+		result.Error = err.Error()
+	} else {
+		c.logger.Infof("calibration of %s: delay %s, confidence %.2f", m.Target, delay.String(), confidence)
+		result.Delay = delay.Nanoseconds()
+		result.Confidence = confidence
+	}
+
+	err = c.Send(result)
+
+	if err != nil {
+		c.logger.Errorf("failed to send calibration result: %v", err)
+	}
+}
+
+func (c *Client) measure(m *messages.Calibration) (time.Duration, float64, error) {
+	if c.recorder == nil {
+		return 0, 0, errors.New("client has no recorder")
+	}
+
+	rate := beep.SampleRate(m.SampleRate)
+
+	// the start is in the time of the server
+	start := timex.ToTime(m.Start).Add(c.clock.GetLatency())
+
+	recording, err := c.recorder.Record(start, time.Duration(m.Duration), rate)
+
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "error recording")
+	}
+
+	return calibration.Measure(recording, rate)
+}
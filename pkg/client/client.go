@@ -1,13 +1,17 @@
 package client
 
 import (
+	"sync"
 	"time"
 
 	"github.com/panjf2000/gnet/v2"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/protobuf/proto"
 
+	"network-audio/pkg/calibration"
 	"network-audio/pkg/client/player"
+	"network-audio/pkg/dsp"
 	"network-audio/pkg/logx"
 	"network-audio/pkg/messages"
 	"network-audio/pkg/timex"
@@ -27,6 +31,35 @@ func WithReconnectInterval(interval time.Duration) ClientOption {
 	}
 }
 
+// WithRecorder enables the client to act as calibration reference, recording the room with the given recorder.
+func WithRecorder(recorder calibration.Recorder) ClientOption {
+	return func(c *Client) {
+		c.recorder = recorder
+	}
+}
+
+// WithNowPlayingHandler registers a function that is called whenever the server announces a track.
+func WithNowPlayingHandler(handler func(m *messages.NowPlaying)) ClientOption {
+	return func(c *Client) {
+		c.nowPlayingHandler = handler
+	}
+}
+
+// WithPlaybackStateHandler subscribes to the playback state and calls the handler for every update.
+func WithPlaybackStateHandler(handler func(m *messages.PlaybackState)) ClientOption {
+	return func(c *Client) {
+		c.playbackStateHandler = handler
+		c.topics = append(c.topics, messages.Topic_TOPIC_PLAYBACK_STATE)
+	}
+}
+
+// WithHostOutputLatency sets the output latency offset of the clients of the given host at the server after connecting.
+func WithHostOutputLatency(host string, latency time.Duration) ClientOption {
+	return func(c *Client) {
+		c.hostOutputLatencies = append(c.hostOutputLatencies, &messages.OutputLatency{Latency: latency.Nanoseconds(), Host: host})
+	}
+}
+
 func WithReconnectMaxTimes(maxTimes int) ClientOption {
 	return func(c *Client) {
 		c.reconnectMaxTimes = maxTimes
@@ -54,6 +87,19 @@ type Client struct {
 	closed    bool
 
 	clock *player.Clock
+
+	recorder calibration.Recorder
+
+	nowPlaying        *messages.NowPlaying
+	nowPlayingHandler func(m *messages.NowPlaying)
+	lock              *sync.RWMutex
+
+	// topics the client subscribes to after connecting
+	topics               []messages.Topic
+	playbackStateHandler func(m *messages.PlaybackState)
+
+	// output latencies the client sets at the server after connecting
+	hostOutputLatencies []*messages.OutputLatency
 }
 
 func New(logger logrus.FieldLogger, clock *player.Clock, player *player.Player, address string, opts ...ClientOption) *Client {
@@ -69,6 +115,7 @@ func New(logger logrus.FieldLogger, clock *player.Clock, player *player.Player,
 		shutdown:          false,
 		player:            player,
 		clock:             clock,
+		lock:              &sync.RWMutex{},
 	}
 
 	for _, opt := range opts {
@@ -145,19 +192,32 @@ func (c *Client) OnBoot(engine gnet.Engine) gnet.Action {
 	return gnet.None
 }
 
+// OnTraffic handles all complete frames, a partial frame stays buffered until the rest arrives.
 func (c *Client) OnTraffic(con gnet.Conn) gnet.Action {
-	msg, err := messages.FromConnection(con)
+	for {
+		msg, err := messages.FromConnection(con)
 
-	if err != nil {
-		c.logger.Error(err)
+		if errors.Is(err, messages.ErrIncompleteFrame) {
+			return gnet.None
+		}
 
-		return gnet.Close
+		// corrupt or unknown frames are skipped, the connection stays in sync
+		if err != nil {
+			c.logger.Error(err)
+
+			continue
+		}
+
+		if action := c.handle(msg); action != gnet.None {
+			return action
+		}
 	}
+}
 
+func (c *Client) handle(msg proto.Message) gnet.Action {
 	switch m := msg.(type) {
 	case *messages.Audio:
-		samples := len(m.Left)
-		sampleDuration := c.player.SampleDuration(samples)
+		sampleDuration := c.player.AudioDuration(m)
 		sent := timex.ToTime(m.Time)
 		playbackTime := sent.Add(sampleDuration)
 		received := c.clock.Now()
@@ -169,9 +229,43 @@ func (c *Client) OnTraffic(con gnet.Conn) gnet.Action {
 			return gnet.None
 		}
 
-		go c.player.Enqueue(m)
+		c.player.Enqueue(m)
 	case *messages.Latency:
 		c.player.UpdateLatency(m)
+	case *messages.OutputLatency:
+		outputLatency := time.Duration(m.Latency)
+
+		// a calibration corrects the offset of the client, which may be set locally
+		if m.Relative {
+			outputLatency += c.player.GetOutputLatency()
+		}
+
+		c.logger.Infof("output latency set to %s", outputLatency.String())
+		c.player.SetOutputLatency(outputLatency)
+	case *messages.DspConfig:
+		config := dspConfigOf(m)
+
+		if err := config.Validate(); err != nil {
+			c.logger.Errorf("dsp config error: %s\n", err)
+			break
+		}
+
+		c.logger.Infof("dsp config updated")
+		c.player.SetDspConfig(config)
+	case *messages.Calibration:
+		go c.calibrate(m)
+	case *messages.NowPlaying:
+		c.lock.Lock()
+		c.nowPlaying = m
+		c.lock.Unlock()
+
+		if c.nowPlayingHandler != nil {
+			c.nowPlayingHandler(m)
+		}
+	case *messages.PlaybackState:
+		if c.playbackStateHandler != nil {
+			c.playbackStateHandler(m)
+		}
 	default:
 		c.logger.Errorf("unknown message type: %T\n", m)
 		return gnet.None
@@ -185,7 +279,27 @@ func (c *Client) OnOpen(con gnet.Conn) ([]byte, gnet.Action) {
 
 	c.logger.Infof("connection opened: %s\n", con.RemoteAddr())
 
-	go c.player.Play()
+	go func() {
+		err := c.player.Play()
+
+		if err != nil {
+			c.logger.Errorf("failed to play: %v", err)
+		}
+	}()
+
+	if len(c.topics) > 0 {
+		err := c.Send(&messages.Subscription{Topics: c.topics})
+
+		if err != nil {
+			c.logger.Errorf("failed to subscribe: %v", err)
+		}
+	}
+
+	for _, m := range c.hostOutputLatencies {
+		if err := c.SetHostOutputLatency(m.Host, time.Duration(m.Latency)); err != nil {
+			c.logger.Errorf("failed to set the output latency of %s: %v", m.Host, err)
+		}
+	}
 
 	return nil, gnet.None
 }
@@ -256,6 +370,20 @@ func (c *Client) Send(msg proto.Message) error {
 	return nil
 }
 
+// SetHostOutputLatency sets the output latency offset of the clients of the given host at the server, the server
+// stores it for the host and sends it to its connected clients.
+func (c *Client) SetHostOutputLatency(host string, latency time.Duration) error {
+	return c.Send(&messages.OutputLatency{Latency: latency.Nanoseconds(), Host: host})
+}
+
+// NowPlaying returns the track last announced by the server or nil.
+func (c *Client) NowPlaying() *messages.NowPlaying {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.nowPlaying
+}
+
 func (c *Client) Shutdown() {
 	c.shutdownChan <- true
 }
@@ -263,3 +391,18 @@ func (c *Client) Shutdown() {
 func (c *Client) Close() {
 	c.closeChan <- true
 }
+
+func dspConfigOf(m *messages.DspConfig) dsp.Config {
+	config := dsp.Config{
+		Bass:     m.Bass,
+		Treble:   m.Treble,
+		HighPass: m.HighPass,
+		Limiter:  m.Limiter,
+	}
+
+	for _, band := range m.Bands {
+		config.Bands = append(config.Bands, dsp.Band{Frequency: band.Frequency, Gain: band.Gain, Q: band.Q})
+	}
+
+	return config
+}
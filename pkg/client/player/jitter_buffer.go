@@ -0,0 +1,225 @@
+package player
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+
+	"network-audio/pkg/audio"
+	"network-audio/pkg/circularbuffer"
+)
+
+// jitterBlock is a received audio block at the rate of the player.
+type jitterBlock struct {
+	start  time.Time
+	frames []audio.Frame
+}
+
+func (b *jitterBlock) end(rate beep.SampleRate) time.Time {
+	return b.start.Add(rate.D(len(b.frames)))
+}
+
+// JitterStats counts what happened to the frames during a read.
+type JitterStats struct {
+	// frames played from the buffer
+	Played int
+	// frames skipped because their play time had passed
+	Dropped int
+	// silent frames before a block that starts in the future
+	Filled int
+	// silent frames because the buffer ran empty
+	Missing int
+	// received blocks that were already buffered or started before the playback position
+	Duplicates int
+}
+
+// JitterBuffer orders the received blocks by their play time and hands out the frames for a playout time.
+// Insert is called by the receiving goroutine and Read by the audio output, both without locks.
+type JitterBuffer struct {
+	rate beep.SampleRate
+
+	// blocks handed from Insert to Read
+	incoming *circularbuffer.Ring[*jitterBlock]
+	pool     *sync.Pool
+
+	// blocks ordered by their start, only used by Read
+	blocks []*jitterBlock
+	index  int
+}
+
+// NewJitterBuffer creates a buffer for frames at the rate that holds up to capacity blocks not yet read.
+func NewJitterBuffer(rate beep.SampleRate, capacity int) *JitterBuffer {
+	return &JitterBuffer{
+		rate:     rate,
+		incoming: circularbuffer.NewRing[*jitterBlock](capacity),
+		pool: &sync.Pool{
+			New: func() any {
+				return &jitterBlock{}
+			},
+		},
+	}
+}
+
+// Insert copies the frames as a block starting at the time of the first frame, it returns false if the buffer is full.
+// It never blocks.
+func (b *JitterBuffer) Insert(frames []audio.Frame) bool {
+	if len(frames) == 0 {
+		return true
+	}
+
+	block := b.pool.Get().(*jitterBlock)
+	block.start = frames[0].Time
+	block.frames = append(block.frames[:0], frames...)
+
+	if !b.incoming.Push(block) {
+		b.pool.Put(block)
+
+		return false
+	}
+
+	return true
+}
+
+// receive moves the incoming blocks into the ordered blocks, discarding duplicates and blocks behind the playback position.
+func (b *JitterBuffer) receive(stats *JitterStats) {
+	for {
+		block, ok := b.incoming.Pop()
+
+		if !ok {
+			return
+		}
+
+		// half a frame of tolerance for the rounding of the timestamps
+		tolerance := b.rate.D(1) / 2
+
+		position := sort.Search(
+			len(b.blocks),
+			func(i int) bool {
+				return !b.blocks[i].start.Before(block.start)
+			},
+		)
+
+		overlapsPrevious := position > 0 && b.blocks[position-1].end(b.rate).Sub(block.start) > tolerance
+		overlapsNext := position < len(b.blocks) && block.end(b.rate).Sub(b.blocks[position].start) > tolerance
+		behindPlayback := position == 0 && len(b.blocks) > 0 && b.index > 0
+
+		if overlapsPrevious || overlapsNext || behindPlayback {
+			stats.Duplicates++
+			b.pool.Put(block)
+
+			continue
+		}
+
+		b.blocks = append(b.blocks, nil)
+		copy(b.blocks[position+1:], b.blocks[position:])
+		b.blocks[position] = block
+	}
+}
+
+// next releases the current block and continues with the following one.
+func (b *JitterBuffer) next() {
+	b.pool.Put(b.blocks[0])
+
+	copy(b.blocks, b.blocks[1:])
+	b.blocks[len(b.blocks)-1] = nil
+	b.blocks = b.blocks[:len(b.blocks)-1]
+	b.index = 0
+}
+
+// Read fills frames with the frames to be played from start on. Frames are read continuously
+// as long as their time is within the threshold of the playout time, otherwise the buffer skips
+// ahead or inserts silence. Silent frames have no channels.
+func (b *JitterBuffer) Read(frames []audio.Frame, start time.Time, threshold time.Duration) JitterStats {
+	stats := JitterStats{}
+
+	b.receive(&stats)
+
+	i := 0
+
+	for i < len(frames) {
+		if len(b.blocks) == 0 {
+			for j := range frames[i:] {
+				frames[i+j] = audio.Frame{}
+			}
+
+			stats.Missing += len(frames) - i
+
+			break
+		}
+
+		block := b.blocks[0]
+
+		if b.index >= len(block.frames) {
+			b.next()
+			continue
+		}
+
+		diff := start.Add(b.rate.D(i)).Sub(block.start.Add(b.rate.D(b.index)))
+
+		if diff >= threshold {
+			// the frame is late, skip to the frame of the playout time
+			skip := b.rate.N(diff)
+
+			if skip < 1 {
+				skip = 1
+			}
+
+			if b.index+skip > len(block.frames) {
+				skip = len(block.frames) - b.index
+			}
+
+			b.index += skip
+			stats.Dropped += skip
+
+			continue
+		}
+
+		if -diff > threshold {
+			// the frame is early, wait for it with silence
+			fill := b.rate.N(-diff)
+
+			if fill < 1 {
+				fill = 1
+			}
+
+			if fill > len(frames)-i {
+				fill = len(frames) - i
+			}
+
+			for j := range frames[i : i+fill] {
+				frames[i+j] = audio.Frame{}
+			}
+
+			i += fill
+			stats.Filled += fill
+
+			continue
+		}
+
+		frames[i] = block.frames[b.index]
+		b.index++
+		i++
+		stats.Played++
+	}
+
+	return stats
+}
+
+// Clear removes all blocks, it must not be called while Read or Insert are running.
+func (b *JitterBuffer) Clear() {
+	for {
+		block, ok := b.incoming.Pop()
+
+		if !ok {
+			break
+		}
+
+		b.pool.Put(block)
+	}
+
+	for len(b.blocks) > 0 {
+		b.next()
+	}
+}
@@ -0,0 +1,80 @@
+package player
+
+import (
+	"testing"
+	"time"
+
+	"github.com/faiface/beep"
+
+	"network-audio/pkg/audio"
+)
+
+const testRate = beep.SampleRate(1000)
+
+// testBlock creates a block of n mono frames starting at start, the sample of each frame is its index in the stream.
+func testBlock(origin time.Time, first int, n int) []audio.Frame {
+	frames := make([]audio.Frame, n)
+
+	for i := range frames {
+		frames[i] = audio.Frame{Channels: 1, Layout: audio.Mono, Time: origin.Add(testRate.D(first + i))}
+		frames[i].Data[0] = float64(first + i)
+	}
+
+	return frames
+}
+
+func TestJitterBuffer_Read(t *testing.T) {
+	origin := time.Now()
+	threshold := time.Millisecond * 5
+
+	t.Run(
+		"should play blocks in the order of their time and discard duplicates",
+		func(t *testing.T) {
+			b := NewJitterBuffer(testRate, 16)
+
+			b.Insert(testBlock(origin, 10, 10))
+			b.Insert(testBlock(origin, 0, 10))
+			b.Insert(testBlock(origin, 10, 10))
+
+			frames := make([]audio.Frame, 20)
+			stats := b.Read(frames, origin, threshold)
+
+			if stats.Played != 20 || stats.Duplicates != 1 {
+				t.Fatalf("unexpected stats %+v", stats)
+			}
+
+			for i, frame := range frames {
+				if frame.Data[0] != float64(i) {
+					t.Fatalf("frame %d has sample %f", i, frame.Data[0])
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"should skip late frames and wait for early ones",
+		func(t *testing.T) {
+			b := NewJitterBuffer(testRate, 16)
+
+			b.Insert(testBlock(origin, 0, 20))
+			b.Insert(testBlock(origin, 40, 10))
+
+			frames := make([]audio.Frame, 30)
+			stats := b.Read(frames, origin.Add(testRate.D(10)), threshold)
+
+			if stats.Dropped != 10 || stats.Played != 10 || stats.Filled != 20 {
+				t.Fatalf("unexpected stats %+v", stats)
+			}
+
+			if frames[0].Data[0] != 10 || frames[9].Data[0] != 19 || frames[10].Channels != 0 {
+				t.Fatalf("unexpected frames %v, %v, %v", frames[0], frames[9], frames[10])
+			}
+
+			stats = b.Read(frames, origin.Add(testRate.D(40)), threshold)
+
+			if stats.Played != 10 || stats.Missing != 20 || frames[0].Data[0] != 40 {
+				t.Fatalf("unexpected stats %+v", stats)
+			}
+		},
+	)
+}
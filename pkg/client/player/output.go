@@ -0,0 +1,211 @@
+package player
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/pkg/errors"
+
+	"network-audio/pkg/wavx"
+)
+
+// Output plays the samples of the player, e.g. on the speaker of package speaker or into a file.
+type Output interface {
+	// Play starts to pull blocks of bufferSize samples from the streamer in real time, it replaces a playing streamer.
+	Play(format beep.Format, bufferSize int, streamer beep.Streamer) error
+
+	// Stop stops pulling samples, the output can be played again afterwards.
+	Stop()
+
+	// Close releases the output.
+	Close() error
+}
+
+// PacedOutput pulls the samples at the pace of the sample rate and hands them to a writer,
+// it replaces the clock of a sound card for outputs without one.
+type PacedOutput struct {
+	write func(format beep.Format, samples [][2]float64) error
+	close func() error
+
+	stopChan chan bool
+	// closed once the running stream stopped pulling samples
+	done chan struct{}
+	lock *sync.Mutex
+}
+
+func newPacedOutput(write func(format beep.Format, samples [][2]float64) error, close func() error) *PacedOutput {
+	return &PacedOutput{
+		write: write,
+		close: close,
+		lock:  &sync.Mutex{},
+	}
+}
+
+// NewNullOutput discards the samples, e.g. to run a client without an audio device.
+func NewNullOutput() *PacedOutput {
+	return newPacedOutput(
+		func(format beep.Format, samples [][2]float64) error {
+			return nil
+		},
+		func() error {
+			return nil
+		},
+	)
+}
+
+// NewPipeOutput writes the samples as raw interleaved PCM to the writer, signed little endian for 16 and 24 bit.
+// The writer is not closed.
+func NewPipeOutput(writer io.Writer) *PacedOutput {
+	return newPacedOutput(
+		func(format beep.Format, samples [][2]float64) error {
+			_, err := writer.Write(wavx.Encode(format, samples))
+
+			return err
+		},
+		func() error {
+			return nil
+		},
+	)
+}
+
+// NewWavOutput writes the samples to a WAV file, the file is created once playback starts.
+func NewWavOutput(path string) *PacedOutput {
+	var wav *wavx.Writer
+
+	return newPacedOutput(
+		func(format beep.Format, samples [][2]float64) error {
+			if wav == nil {
+				file, err := os.Create(path)
+
+				if err != nil {
+					return err
+				}
+
+				wav, err = wavx.NewWriter(file, format)
+
+				if err != nil {
+					_ = file.Close()
+
+					return err
+				}
+			}
+
+			return wav.Write(samples)
+		},
+		func() error {
+			if wav == nil {
+				return nil
+			}
+
+			return wav.Close()
+		},
+	)
+}
+
+func (o *PacedOutput) Play(format beep.Format, bufferSize int, streamer beep.Streamer) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	// like the speaker, a reconnecting client replaces the running stream, the streamer has a single consumer
+	o.stop()
+
+	o.stopChan = make(chan bool)
+	o.done = make(chan struct{})
+
+	go o.run(format, bufferSize, streamer, o.stopChan, o.done)
+
+	return nil
+}
+
+// run streams against an absolute schedule, so the pace does not drift with the time spent streaming and writing.
+func (o *PacedOutput) run(format beep.Format, bufferSize int, streamer beep.Streamer, stopChan chan bool, done chan struct{}) {
+	defer close(done)
+
+	samples := make([][2]float64, bufferSize)
+	start := time.Now()
+	frames := 0
+
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-time.After(time.Until(start.Add(format.SampleRate.D(frames)))):
+		}
+
+		n, ok := streamer.Stream(samples)
+		err := o.write(format, samples[:n])
+
+		if err != nil || !ok {
+			return
+		}
+
+		frames += n
+	}
+}
+
+func (o *PacedOutput) Stop() {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.stop()
+}
+
+// stop ends the running stream while the lock is held and waits until it stopped streaming and writing.
+func (o *PacedOutput) stop() {
+	if o.stopChan == nil {
+		return
+	}
+
+	close(o.stopChan)
+	<-o.done
+
+	o.stopChan = nil
+	o.done = nil
+}
+
+func (o *PacedOutput) Close() error {
+	o.Stop()
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	return o.close()
+}
+
+// ParseOutput returns the headless output of a flag value: null, pcm for raw PCM on stdout, pcm:<file> or wav:<file>.
+// The speaker is provided by package speaker.
+func ParseOutput(value string) (Output, error) {
+	name, path, _ := strings.Cut(value, ":")
+
+	switch name {
+	case "null":
+		return NewNullOutput(), nil
+	case "pcm":
+		if path == "" || path == "-" {
+			return NewPipeOutput(os.Stdout), nil
+		}
+
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+
+		if err != nil {
+			return nil, err
+		}
+
+		o := NewPipeOutput(file)
+		o.close = file.Close
+
+		return o, nil
+	case "wav":
+		if path == "" {
+			return nil, errors.New("wav output requires a file, e.g. wav:out.wav")
+		}
+
+		return NewWavOutput(path), nil
+	default:
+		return nil, errors.Errorf("unknown output %q, expected null, pcm, pcm:<file> or wav:<file>", value)
+	}
+}
@@ -0,0 +1,86 @@
+package player
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// testStreamer blocks in Stream until it is released, like a consumer of the ring it must not be called concurrently.
+type testStreamer struct {
+	active   int32
+	overlaps int32
+	streams  int
+	entered  chan struct{}
+	release  chan struct{}
+}
+
+func (s *testStreamer) Stream(samples [][2]float64) (int, bool) {
+	if atomic.AddInt32(&s.active, 1) > 1 {
+		atomic.AddInt32(&s.overlaps, 1)
+	}
+
+	defer atomic.AddInt32(&s.active, -1)
+
+	// not synchronized on purpose, the race detector reports concurrent streams
+	s.streams++
+
+	select {
+	case s.entered <- struct{}{}:
+	default:
+	}
+
+	<-s.release
+
+	return len(samples), true
+}
+
+func (s *testStreamer) Err() error {
+	return nil
+}
+
+func TestPacedOutput_Play(t *testing.T) {
+	t.Run(
+		"should stop the running stream before it plays again",
+		func(t *testing.T) {
+			format := beep.Format{SampleRate: testRate, NumChannels: 2, Precision: 2}
+			streamer := &testStreamer{entered: make(chan struct{}), release: make(chan struct{})}
+			o := NewNullOutput()
+
+			if err := o.Play(format, 16, streamer); err != nil {
+				t.Fatal(err)
+			}
+
+			<-streamer.entered
+
+			played := make(chan struct{})
+
+			go func() {
+				_ = o.Play(format, 16, streamer)
+				close(played)
+			}()
+
+			select {
+			case <-played:
+				t.Fatal("expected the second play to wait for the running stream")
+			case <-time.After(time.Millisecond * 50):
+			}
+
+			close(streamer.release)
+			<-played
+
+			// the replacing stream pulls a few blocks
+			time.Sleep(time.Millisecond * 50)
+
+			if err := o.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if overlaps := atomic.LoadInt32(&streamer.overlaps); overlaps > 0 {
+				t.Errorf("expected one stream at a time, got %d overlapping streams", overlaps)
+			}
+		},
+	)
+}
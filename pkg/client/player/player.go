@@ -1,14 +1,15 @@
 package player
 
 import (
+	"sync"
 	"time"
 
 	"github.com/faiface/beep"
-	"github.com/faiface/beep/speaker"
 	"github.com/sirupsen/logrus"
 
 	"network-audio/pkg/audio"
-	"network-audio/pkg/circularbuffer"
+	"network-audio/pkg/capture"
+	"network-audio/pkg/dsp"
 	"network-audio/pkg/messages"
 	"network-audio/pkg/timex"
 )
@@ -16,7 +17,12 @@ import (
 type Player struct {
 	logger logrus.FieldLogger
 
-	streamBuffer *circularbuffer.Queue
+	// Blocks ordered by their play time, written by Enqueue and read by Stream
+	streamBuffer *JitterBuffer
+
+	// Reused frames of the producer and the consumer of the stream buffer
+	enqueueFrames []audio.Frame
+	streamFrames  []audio.Frame
 
 	// Will be used to fill the player buffer if the available data is not enough
 	fillStreamer beep.Streamer
@@ -30,6 +36,28 @@ type Player struct {
 
 	// This threshold defines the maximum distance between the next packet and now
 	delayThreshold time.Duration
+
+	// The delay added by the output device (DAC, bluetooth sink, receiver, ...)
+	// samples are handed to the speaker this much earlier to compensate it
+	outputLatency time.Duration
+	lock          *sync.RWMutex
+
+	// Maps the channels of the stream to the speaker outputs
+	channelMap audio.ChannelMap
+
+	// The effect chain applied before the speaker
+	dspConfig dsp.Config
+	effects   dsp.Chain
+
+	// Converts streams with a different sample rate to the rate of the speaker
+	resampler   *audio.Resampler
+	enqueueLock *sync.Mutex
+
+	// Plays the stream, nowhere by default
+	output Output
+
+	// Records the samples handed to the speaker
+	recording *capture.Capture
 }
 
 type Option func(*Player)
@@ -52,6 +80,38 @@ func WithBufferSize(bufferSize int) Option {
 	}
 }
 
+func WithOutputLatency(outputLatency time.Duration) Option {
+	return func(p *Player) {
+		p.outputLatency = outputLatency
+	}
+}
+
+func WithChannelMap(channelMap audio.ChannelMap) Option {
+	return func(p *Player) {
+		p.channelMap = channelMap
+	}
+}
+
+func WithDspConfig(dspConfig dsp.Config) Option {
+	return func(p *Player) {
+		p.dspConfig = dspConfig
+	}
+}
+
+// WithRecording records the samples handed to the speaker with the time they become audible, the caller closes the capture.
+func WithRecording(recording *capture.Capture) Option {
+	return func(p *Player) {
+		p.recording = recording
+	}
+}
+
+// WithOutput sets the output of the samples, without one they are discarded.
+func WithOutput(output Output) Option {
+	return func(p *Player) {
+		p.output = output
+	}
+}
+
 func WithFillStreamer(fillStreamer beep.Streamer) Option {
 	return func(p *Player) {
 		p.fillStreamer = fillStreamer
@@ -68,19 +128,26 @@ func New(logger logrus.FieldLogger, clock *Clock, opts ...Option) *Player {
 		// endless silence streamer
 		fillStreamer: beep.Silence(-1),
 
-		clock: clock,
+		clock:       clock,
+		lock:        &sync.RWMutex{},
+		enqueueLock: &sync.Mutex{},
+		channelMap:  audio.StereoMap,
+		output:      NewNullOutput(),
 	}
 
 	for _, opt := range opts {
 		opt(p)
 	}
 
-	// create a buffer with a size of 10 seconds of audio based on the format
-	streamBufferSize := p.format.SampleRate.N(time.Millisecond * 500)
-	p.streamBuffer = circularbuffer.New(streamBufferSize)
+	p.effects = p.dspConfig.Chain(p.format.SampleRate)
 
-	p.logger.Infof("Client: streamBufferSize: %d", streamBufferSize)
+	// create a buffer for 1024 blocks, about 12 seconds of audio in blocks of 512 samples at 44.1 kHz
+	streamBufferSize := 1024
+	p.streamBuffer = NewJitterBuffer(p.format.SampleRate, streamBufferSize)
+
+	p.logger.Infof("Client: streamBufferSize: %d blocks", streamBufferSize)
 	p.logger.Infof("Client: playerBufferSize: %d", p.bufferSize)
+	p.logger.Infof("Client: outputLatency: %s", p.outputLatency)
 
 	return p
 }
@@ -93,28 +160,135 @@ func (p *Player) UpdateLatency(m *messages.Latency) time.Duration {
 	return p.clock.UpdateLatency(m)
 }
 
+func (p *Player) GetOutputLatency() time.Duration {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.outputLatency
+}
+
+func (p *Player) SetOutputLatency(outputLatency time.Duration) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.outputLatency = outputLatency
+}
+
+func (p *Player) GetDspConfig() dsp.Config {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.dspConfig
+}
+
+// SetDspConfig replaces the effect chain, the new chain starts with a clean filter state.
+func (p *Player) SetDspConfig(dspConfig dsp.Config) {
+	effects := dspConfig.Chain(p.format.SampleRate)
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.dspConfig = dspConfig
+	p.effects = effects
+}
+
+func (p *Player) getEffects() dsp.Chain {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.effects
+}
+
+// now returns the synchronized time at which a sample handed to the speaker right now becomes audible.
+func (p *Player) now() time.Time {
+	return p.clock.Now().Add(p.GetOutputLatency())
+}
+
+// StreamRate returns the sample rate of the audio message, messages without a rate are played at the rate of the player.
+func (p *Player) StreamRate(am *messages.Audio) beep.SampleRate {
+	if am.SampleRate == 0 {
+		return p.format.SampleRate
+	}
+
+	return beep.SampleRate(am.SampleRate)
+}
+
+// AudioDuration returns the playback duration of the audio message.
+func (p *Player) AudioDuration(am *messages.Audio) time.Duration {
+	return p.StreamRate(am).D(am.Frames())
+}
+
 func (p *Player) Enqueue(am *messages.Audio) {
-	for index, leftData := range am.Left {
-		data := [2]float64{
-			leftData,
-			am.Right[index],
-		}
+	p.enqueueLock.Lock()
+	defer p.enqueueLock.Unlock()
 
-		offset := p.SampleDuration(index)
-		s := audio.New(data, timex.ToTime(am.Time).Add(offset))
+	layout := audio.Layout(am.Layout)
+	rate := p.StreamRate(am)
+	start := timex.ToTime(am.Time)
+	channels := len(am.Channels)
 
-		if time.Since(s.Time) > time.Millisecond*100 {
-			break
+	if channels > audio.MaxChannels {
+		channels = audio.MaxChannels
+	}
+
+	p.enqueueFrames = p.enqueueFrames[:0]
+
+	if rate == p.format.SampleRate {
+		for index := 0; index < am.Frames(); index++ {
+			frame := audio.Frame{Channels: channels, Layout: layout, Time: start.Add(rate.D(index))}
+
+			for channel := 0; channel < channels; channel++ {
+				frame.Data[channel] = am.Channels[channel].Samples[index]
+			}
+
+			p.enqueueFrames = append(p.enqueueFrames, frame)
 		}
 
-		p.streamBuffer.Enqueue(s)
+		p.insert()
+
+		return
+	}
+
+	if p.resampler == nil || p.resampler.From() != rate {
+		p.logger.Infof("resampling stream from %d Hz to %d Hz", rate, p.format.SampleRate)
+		p.resampler = audio.NewResampler(rate, p.format.SampleRate)
 	}
+
+	frames := make([][]float64, am.Frames())
+
+	for index := range frames {
+		frames[index] = make([]float64, channels)
+
+		for channel := range frames[index] {
+			frames[index][channel] = am.Channels[channel].Samples[index]
+		}
+	}
+
+	p.resampler.Resample(
+		frames,
+		func(data []float64, position float64) {
+			offset := time.Duration(position * float64(time.Second) / float64(rate))
+			frame := audio.Frame{Channels: len(data), Layout: layout, Time: start.Add(offset)}
+			copy(frame.Data[:], data)
+
+			p.enqueueFrames = append(p.enqueueFrames, frame)
+		},
+	)
+
+	p.insert()
 }
 
-func (p *Player) Play() {
-	_ = speaker.Init(p.format.SampleRate, p.bufferSize)
+// insert adds the enqueued frames as block to the stream buffer.
+func (p *Player) insert() {
+	if !p.streamBuffer.Insert(p.enqueueFrames) {
+		p.logger.Warnf("stream buffer is full, dropping %d samples", len(p.enqueueFrames))
+	}
+}
 
-	speaker.Play(
+func (p *Player) Play() error {
+	return p.output.Play(
+		p.format,
+		p.bufferSize,
 		beep.Seq(
 			beep.Callback(
 				func() {
@@ -131,58 +305,67 @@ func (p *Player) Play() {
 	)
 }
 
+// Close stops the output. The stream buffer is left as it is, the connection may still insert into it and the
+// output may still read from it, blocks of the past are dropped by the next read anyway.
 func (p *Player) Close() {
-	p.streamBuffer.Clear()
-	speaker.Clear()
+	p.output.Stop()
 }
 
 func (p *Player) Stream(samples [][2]float64) (n int, ok bool) {
-	requiredSamples := len(samples)
-	fillSamples := 0
-	droppedSamples := 0
+	if cap(p.streamFrames) < len(samples) {
+		p.streamFrames = make([]audio.Frame, len(samples))
+	}
 
-	now := p.clock.Now()
-	as := p.streamBuffer.Peek().(*audio.Sample)
+	frames := p.streamFrames[:len(samples)]
+	start := p.now()
 
-	// drop samples until the first sample is in the delay threshold
-	for now.Sub(as.Time) >= p.delayThreshold {
-		droppedSamples++
-		_ = p.streamBuffer.Dequeue().(*audio.Sample)
-		now = p.clock.Now()
-		as = p.streamBuffer.Peek().(*audio.Sample)
-	}
+	stats := p.streamBuffer.Read(frames, start, p.delayThreshold)
+
+	// play the frames and fill the gaps between them from the fillStreamer
+	for i := 0; i < len(frames); {
+		if frames[i].Channels > 0 {
+			samples[i] = p.channelMap(frames[i].Samples(), frames[i].Layout)
+			i++
 
-	// we are behind the delay threshold, so we need to fill the buffer with samples from the fillStreamer
-	diff := as.Time.Sub(now)
+			continue
+		}
 
-	if diff > p.delayThreshold {
-		fillSamples = p.format.SampleRate.N(diff)
+		gap := i + 1
 
-		// take the lower one
-		if requiredSamples < fillSamples {
-			fillSamples = requiredSamples
+		for gap < len(frames) && frames[gap].Channels == 0 {
+			gap++
 		}
 
-		p.fillStreamer.Stream(samples[:fillSamples])
+		p.fillStreamer.Stream(samples[i:gap])
+		i = gap
 	}
 
-	for i := fillSamples; i < requiredSamples; i++ {
-		as := p.streamBuffer.Dequeue()
+	if stats.Filled > 0 {
+		p.logger.Warnf("filled %d samples", stats.Filled)
+	}
 
-		if as == nil {
-			p.logger.Warnf("buffer underflow, dropping samples")
-			break
-		}
+	if stats.Missing > 0 && stats.Played > 0 {
+		p.logger.Warnf("buffer underflow, filled %d samples", stats.Missing)
+	}
+
+	if stats.Dropped > 0 {
+		p.logger.Warnf("dropped %d samples", stats.Dropped)
+	}
 
-		samples[i] = as.(*audio.Sample).Data
+	if stats.Duplicates > 0 {
+		p.logger.Warnf("discarded %d duplicate blocks", stats.Duplicates)
 	}
 
-	if fillSamples > 0 {
-		p.logger.Warnf("filled %d samples", droppedSamples)
+	if effects := p.getEffects(); effects != nil {
+		effects.Process(samples)
 	}
 
-	if droppedSamples > 0 {
-		p.logger.Warnf("dropped %d samples", droppedSamples)
+	if p.recording != nil {
+		err := p.recording.Write(samples, start)
+
+		if err != nil {
+			p.logger.Errorf("recording error: %s", err)
+		}
 	}
 
 	return len(samples), len(samples) > 0
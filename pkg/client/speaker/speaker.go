@@ -0,0 +1,38 @@
+// Package speaker plays on the default audio device. It is kept apart from the player, so headless clients and the
+// tests of the player build without the audio libraries of the system, e.g. the ALSA headers.
+package speaker
+
+import (
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/speaker"
+	"github.com/pkg/errors"
+)
+
+// Output plays on the default audio device, it is an output of the player.
+type Output struct{}
+
+func NewOutput() *Output {
+	return &Output{}
+}
+
+func (o *Output) Play(format beep.Format, bufferSize int, streamer beep.Streamer) error {
+	err := speaker.Init(format.SampleRate, bufferSize)
+
+	if err != nil {
+		return errors.Wrap(err, "error initializing speaker")
+	}
+
+	speaker.Play(streamer)
+
+	return nil
+}
+
+func (o *Output) Stop() {
+	speaker.Clear()
+}
+
+func (o *Output) Close() error {
+	speaker.Close()
+
+	return nil
+}
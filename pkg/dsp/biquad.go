@@ -0,0 +1,33 @@
+package dsp
+
+// Biquad is a second order IIR filter for stereo samples in transposed direct form II.
+type Biquad struct {
+	b0, b1, b2 float64
+	a1, a2     float64
+
+	// state per channel
+	z1, z2 [2]float64
+}
+
+// NewBiquad creates a filter from coefficients normalized to a0 = 1.
+func NewBiquad(b0, b1, b2, a1, a2 float64) *Biquad {
+	return &Biquad{b0: b0, b1: b1, b2: b2, a1: a1, a2: a2}
+}
+
+func (f *Biquad) Process(samples [][2]float64) {
+	for i := range samples {
+		for c := 0; c < 2; c++ {
+			in := samples[i][c]
+			out := f.b0*in + f.z1[c]
+
+			f.z1[c] = f.b1*in - f.a1*out + f.z2[c]
+			f.z2[c] = f.b2*in - f.a2*out
+			samples[i][c] = out
+		}
+	}
+}
+
+func (f *Biquad) Reset() {
+	f.z1 = [2]float64{}
+	f.z2 = [2]float64{}
+}
@@ -0,0 +1,16 @@
+package dsp
+
+// Chain runs processors one after another.
+type Chain []Processor
+
+func (c Chain) Process(samples [][2]float64) {
+	for _, processor := range c {
+		processor.Process(samples)
+	}
+}
+
+func (c Chain) Reset() {
+	for _, processor := range c {
+		processor.Reset()
+	}
+}
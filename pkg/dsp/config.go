@@ -0,0 +1,99 @@
+package dsp
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/faiface/beep"
+)
+
+// ErrInvalidLimiter is returned for a limiter threshold above 0 dBFS, the limiter only lowers the signal.
+var ErrInvalidLimiter = errors.New("limiter threshold must be below 0 dBFS")
+
+const (
+	bassFrequency   = 100.0
+	trebleFrequency = 10000.0
+)
+
+// Band is a band of the parametric equalizer.
+type Band struct {
+	Frequency float64 `json:"frequency"`
+	Gain      float64 `json:"gain"`
+	Q         float64 `json:"q"`
+}
+
+// Config describes the effect chain of a client.
+type Config struct {
+	Bands []Band `json:"bands"`
+
+	// Gain of the shelving filters in dB
+	Bass   float64 `json:"bass"`
+	Treble float64 `json:"treble"`
+
+	// Frequency of the high-pass crossover, 0 disables it
+	HighPass float64 `json:"highPass"`
+
+	// Threshold of the soft limiter in dBFS, 0 disables it, positive thresholds are invalid
+	Limiter float64 `json:"limiter"`
+}
+
+// ReadConfig reads a config from a JSON file.
+func ReadConfig(path string) (Config, error) {
+	config := Config{}
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return config, err
+	}
+
+	err = json.Unmarshal(data, &config)
+
+	if err != nil {
+		return config, err
+	}
+
+	return config, config.Validate()
+}
+
+// Validate returns an error for settings the chain can not apply.
+func (c Config) Validate() error {
+	if c.Limiter > 0 {
+		return ErrInvalidLimiter
+	}
+
+	return nil
+}
+
+// Chain builds the effect chain for the sample rate: crossover, equalizer, shelving filters and the limiter last.
+func (c Config) Chain(rate beep.SampleRate) Chain {
+	chain := Chain{}
+
+	if c.HighPass > 0 {
+		chain = append(chain, Crossover(rate, c.HighPass))
+	}
+
+	for _, band := range c.Bands {
+		q := band.Q
+
+		if q <= 0 {
+			q = 1
+		}
+
+		chain = append(chain, Peaking(rate, band.Frequency, q, band.Gain))
+	}
+
+	if c.Bass != 0 {
+		chain = append(chain, LowShelf(rate, bassFrequency, c.Bass))
+	}
+
+	if c.Treble != 0 {
+		chain = append(chain, HighShelf(rate, trebleFrequency, c.Treble))
+	}
+
+	if c.Limiter < 0 {
+		chain = append(chain, NewSoftLimiter(c.Limiter))
+	}
+
+	return chain
+}
@@ -0,0 +1,15 @@
+package dsp
+
+import (
+	"math"
+)
+
+// FromDecibel converts a gain in dB to a linear factor.
+func FromDecibel(db float64) float64 {
+	return math.Pow(10, db/20)
+}
+
+// ToDecibel converts a linear factor to dB.
+func ToDecibel(gain float64) float64 {
+	return 20 * math.Log10(gain)
+}
@@ -0,0 +1,72 @@
+package dsp
+
+import (
+	"math"
+
+	"github.com/faiface/beep"
+)
+
+// The filters follow the Audio EQ Cookbook by Robert Bristow-Johnson.
+
+func cookbook(rate beep.SampleRate, frequency, q float64) (cos, alpha float64) {
+	w0 := 2 * math.Pi * frequency / float64(rate)
+
+	return math.Cos(w0), math.Sin(w0) / (2 * q)
+}
+
+func normalized(b0, b1, b2, a0, a1, a2 float64) *Biquad {
+	return NewBiquad(b0/a0, b1/a0, b2/a0, a1/a0, a2/a0)
+}
+
+// Peaking boosts or cuts a band around the frequency.
+func Peaking(rate beep.SampleRate, frequency, q, gain float64) *Biquad {
+	a := math.Pow(10, gain/40)
+	cos, alpha := cookbook(rate, frequency, q)
+
+	return normalized(
+		1+alpha*a, -2*cos, 1-alpha*a,
+		1+alpha/a, -2*cos, 1-alpha/a,
+	)
+}
+
+// LowShelf boosts or cuts everything below the frequency.
+func LowShelf(rate beep.SampleRate, frequency, gain float64) *Biquad {
+	a := math.Pow(10, gain/40)
+	cos, alpha := cookbook(rate, frequency, math.Sqrt2/2)
+	sq := 2 * math.Sqrt(a) * alpha
+
+	return normalized(
+		a*((a+1)-(a-1)*cos+sq), 2*a*((a-1)-(a+1)*cos), a*((a+1)-(a-1)*cos-sq),
+		(a+1)+(a-1)*cos+sq, -2*((a-1)+(a+1)*cos), (a+1)+(a-1)*cos-sq,
+	)
+}
+
+// HighShelf boosts or cuts everything above the frequency.
+func HighShelf(rate beep.SampleRate, frequency, gain float64) *Biquad {
+	a := math.Pow(10, gain/40)
+	cos, alpha := cookbook(rate, frequency, math.Sqrt2/2)
+	sq := 2 * math.Sqrt(a) * alpha
+
+	return normalized(
+		a*((a+1)+(a-1)*cos+sq), -2*a*((a-1)+(a+1)*cos), a*((a+1)+(a-1)*cos-sq),
+		(a+1)-(a-1)*cos+sq, 2*((a-1)-(a+1)*cos), (a+1)-(a-1)*cos-sq,
+	)
+}
+
+// HighPass removes everything below the frequency.
+func HighPass(rate beep.SampleRate, frequency, q float64) *Biquad {
+	cos, alpha := cookbook(rate, frequency, q)
+
+	return normalized(
+		(1+cos)/2, -(1 + cos), (1+cos)/2,
+		1+alpha, -2*cos, 1-alpha,
+	)
+}
+
+// Crossover is a 4th order Linkwitz-Riley high-pass, e.g. to protect small speakers from low frequencies.
+func Crossover(rate beep.SampleRate, frequency float64) Chain {
+	return Chain{
+		HighPass(rate, frequency, math.Sqrt2/2),
+		HighPass(rate, frequency, math.Sqrt2/2),
+	}
+}
@@ -0,0 +1,97 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/faiface/beep"
+)
+
+const rate = beep.SampleRate(48000)
+
+// gainAt returns the gain of the processor for a sine of the given frequency in dB.
+func gainAt(processor Processor, frequency float64) float64 {
+	samples := make([][2]float64, rate.N(1e9))
+
+	for i := range samples {
+		v := math.Sin(2 * math.Pi * frequency * float64(i) / float64(rate))
+		samples[i] = [2]float64{v, v}
+	}
+
+	processor.Process(samples)
+
+	// skip the settling of the filter
+	peak := 0.0
+
+	for _, sample := range samples[len(samples)/2:] {
+		peak = math.Max(peak, math.Abs(sample[0]))
+	}
+
+	return ToDecibel(peak)
+}
+
+func TestPeaking(t *testing.T) {
+	t.Run(
+		"should boost the center frequency by the gain",
+		func(t *testing.T) {
+			if g := gainAt(Peaking(rate, 1000, 1, 6), 1000); math.Abs(g-6) > 0.1 {
+				t.Fatalf("gain at 1000 Hz is %.2f dB, expected 6 dB", g)
+			}
+
+			if g := gainAt(Peaking(rate, 1000, 1, 6), 10000); math.Abs(g) > 0.5 {
+				t.Fatalf("gain at 10000 Hz is %.2f dB, expected 0 dB", g)
+			}
+		},
+	)
+}
+
+func TestCrossover(t *testing.T) {
+	t.Run(
+		"should remove frequencies below the crossover",
+		func(t *testing.T) {
+			if g := gainAt(Crossover(rate, 120), 30); g > -40 {
+				t.Fatalf("gain at 30 Hz is %.2f dB, expected less than -40 dB", g)
+			}
+
+			if g := gainAt(Crossover(rate, 120), 2000); math.Abs(g) > 0.1 {
+				t.Fatalf("gain at 2000 Hz is %.2f dB, expected 0 dB", g)
+			}
+		},
+	)
+}
+
+func TestSoftLimiter(t *testing.T) {
+	t.Run(
+		"should keep the signal at or below 0 dBFS",
+		func(t *testing.T) {
+			samples := [][2]float64{{0.1, -0.1}, {2, -2}, {10, -10}}
+
+			NewSoftLimiter(-6).Process(samples)
+
+			if samples[0][0] != 0.1 || samples[0][1] != -0.1 {
+				t.Fatal("signal below the threshold changed")
+			}
+
+			for _, sample := range samples[1:] {
+				if math.Abs(sample[0]) > 1 || math.Abs(sample[1]) > 1 {
+					t.Fatalf("sample %v is not limited", sample)
+				}
+			}
+		},
+	)
+}
+
+func TestConfig(t *testing.T) {
+	t.Run(
+		"should reject a limiter threshold above 0 dBFS",
+		func(t *testing.T) {
+			if err := (Config{Limiter: 3}).Validate(); err != ErrInvalidLimiter {
+				t.Fatalf("expected ErrInvalidLimiter, got %v", err)
+			}
+
+			if err := (Config{Limiter: -3}).Validate(); err != nil {
+				t.Fatal(err)
+			}
+		},
+	)
+}
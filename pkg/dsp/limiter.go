@@ -0,0 +1,97 @@
+package dsp
+
+import (
+	"math"
+
+	"github.com/faiface/beep"
+)
+
+// Limiter is a look-ahead true-peak limiter, it delays the signal by the look-ahead to reduce the gain before a peak.
+type Limiter struct {
+	ceiling float64
+	release float64
+
+	lookahead int
+	delay     [][2]float64
+	position  int
+
+	// required gain of the recent samples, the window covers the delayed samples and the peaks between them
+	required         []float64
+	requiredPosition int
+
+	history  [2][3]float64
+	envelope float64
+}
+
+// NewLimiter creates a limiter keeping the true peak below the ceiling in dBTP.
+func NewLimiter(rate beep.SampleRate, ceiling float64) *Limiter {
+	lookahead := rate.N(secondsToDuration(0.005))
+
+	l := &Limiter{
+		ceiling:   FromDecibel(ceiling),
+		release:   math.Exp(-1 / (float64(rate) * 0.1)),
+		lookahead: lookahead,
+		delay:     make([][2]float64, lookahead),
+		required:  make([]float64, lookahead+3),
+	}
+
+	l.Reset()
+
+	return l
+}
+
+func (l *Limiter) Process(samples [][2]float64) {
+	for i, sample := range samples {
+		peak := 0.0
+
+		for c := 0; c < 2; c++ {
+			peak = math.Max(peak, math.Abs(sample[c]))
+			peak = math.Max(peak, truePeak(l.history[c][0], l.history[c][1], l.history[c][2], sample[c]))
+			l.history[c][0], l.history[c][1], l.history[c][2] = l.history[c][1], l.history[c][2], sample[c]
+		}
+
+		required := 1.0
+
+		if peak > l.ceiling {
+			required = l.ceiling / peak
+		}
+
+		l.required[l.requiredPosition] = required
+		l.requiredPosition = (l.requiredPosition + 1) % len(l.required)
+
+		delayed := l.delay[l.position]
+		l.delay[l.position] = sample
+		l.position = (l.position + 1) % l.lookahead
+
+		// the lowest gain required by any sample in the look-ahead window
+		target := 1.0
+
+		for _, r := range l.required {
+			target = math.Min(target, r)
+		}
+
+		if target < l.envelope {
+			l.envelope = target
+		} else {
+			l.envelope = target + (l.envelope-target)*l.release
+		}
+
+		samples[i][0] = delayed[0] * l.envelope
+		samples[i][1] = delayed[1] * l.envelope
+	}
+}
+
+func (l *Limiter) Reset() {
+	for i := range l.delay {
+		l.delay[i] = [2]float64{}
+	}
+
+	for i := range l.required {
+		l.required[i] = 1
+	}
+
+	l.history = [2][3]float64{}
+	l.position = 0
+	l.requiredPosition = 0
+	l.envelope = 1
+}
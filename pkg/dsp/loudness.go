@@ -0,0 +1,153 @@
+package dsp
+
+import (
+	"math"
+
+	"github.com/faiface/beep"
+)
+
+const (
+	// blocks of 400ms with an overlap of 75% as defined by ITU-R BS.1770
+	loudnessStep         = 0.1
+	loudnessStepsInBlock = 4
+
+	absoluteGate = -70.0
+	relativeGate = -10.0
+)
+
+// LoudnessMeter measures the integrated loudness (EBU R128 / ITU-R BS.1770) and the true peak of a stereo signal.
+type LoudnessMeter struct {
+	weighting [2]*Biquad
+
+	stepSize int
+	// squared sum of the K-weighted samples of the current step
+	stepEnergy float64
+	stepLength int
+	// mean square of the last steps
+	steps []float64
+
+	// mean square of every gating block
+	blocks []float64
+
+	peak *peakDetector
+}
+
+func NewLoudnessMeter(rate beep.SampleRate) *LoudnessMeter {
+	return &LoudnessMeter{
+		weighting: kWeighting(float64(rate)),
+		stepSize:  rate.N(secondsToDuration(loudnessStep)),
+		peak:      &peakDetector{},
+	}
+}
+
+// kWeighting creates the pre-filter and the RLB high-pass of ITU-R BS.1770 for any sample rate.
+func kWeighting(rate float64) [2]*Biquad {
+	f0 := 1681.974450955533
+	g := 3.999843853973347
+	q := 0.7071752369554196
+
+	k := math.Tan(math.Pi * f0 / rate)
+	vh := math.Pow(10, g/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + k/q + k*k
+
+	shelf := NewBiquad(
+		(vh+vb*k/q+k*k)/a0,
+		2*(k*k-vh)/a0,
+		(vh-vb*k/q+k*k)/a0,
+		2*(k*k-1)/a0,
+		(1-k/q+k*k)/a0,
+	)
+
+	f0 = 38.13547087602444
+	q = 0.5003270373238773
+	k = math.Tan(math.Pi * f0 / rate)
+	a0 = 1 + k/q + k*k
+
+	highPass := NewBiquad(1, -2, 1, 2*(k*k-1)/a0, (1-k/q+k*k)/a0)
+
+	return [2]*Biquad{shelf, highPass}
+}
+
+// Write adds the samples to the measurement.
+func (m *LoudnessMeter) Write(samples [][2]float64) {
+	m.peak.Process(samples)
+
+	weighted := make([][2]float64, len(samples))
+	copy(weighted, samples)
+
+	for _, filter := range m.weighting {
+		filter.Process(weighted)
+	}
+
+	for _, sample := range weighted {
+		m.stepEnergy += sample[0]*sample[0] + sample[1]*sample[1]
+		m.stepLength++
+
+		if m.stepLength < m.stepSize {
+			continue
+		}
+
+		m.steps = append(m.steps, m.stepEnergy/float64(m.stepLength))
+		m.stepEnergy = 0
+		m.stepLength = 0
+
+		if len(m.steps) > loudnessStepsInBlock {
+			m.steps = m.steps[1:]
+		}
+
+		if len(m.steps) == loudnessStepsInBlock {
+			block := 0.0
+
+			for _, step := range m.steps {
+				block += step
+			}
+
+			m.blocks = append(m.blocks, block/loudnessStepsInBlock)
+		}
+	}
+}
+
+// Integrated returns the gated integrated loudness in LUFS, or -Inf if the signal is silent.
+func (m *LoudnessMeter) Integrated() float64 {
+	mean := func(threshold float64) float64 {
+		sum := 0.0
+		count := 0
+
+		for _, block := range m.blocks {
+			if loudness(block) > threshold {
+				sum += block
+				count++
+			}
+		}
+
+		if count == 0 {
+			return 0
+		}
+
+		return sum / float64(count)
+	}
+
+	absolute := mean(absoluteGate)
+
+	if absolute == 0 {
+		return math.Inf(-1)
+	}
+
+	relative := mean(loudness(absolute) + relativeGate)
+
+	if relative == 0 {
+		return math.Inf(-1)
+	}
+
+	return loudness(relative)
+}
+
+// TruePeak returns the estimated inter-sample peak as linear factor.
+func (m *LoudnessMeter) TruePeak() float64 {
+	return m.peak.max
+}
+
+func loudness(meanSquare float64) float64 {
+	return -0.691 + 10*math.Log10(meanSquare)
+}
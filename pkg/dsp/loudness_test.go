@@ -0,0 +1,138 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+// sine returns the seconds of a stereo sine with the peak amplitude in dBFS and the phase in radians.
+func sine(frequency, amplitude, phase, seconds float64) [][2]float64 {
+	samples := make([][2]float64, int(seconds*float64(rate)))
+	gain := FromDecibel(amplitude)
+
+	for i := range samples {
+		v := gain * math.Sin(2*math.Pi*frequency*float64(i)/float64(rate)+phase)
+		samples[i] = [2]float64{v, v}
+	}
+
+	return samples
+}
+
+func TestLoudnessMeter(t *testing.T) {
+	t.Run(
+		"should measure a stereo sine of 1 kHz at -23 dBFS with -23 LUFS",
+		func(t *testing.T) {
+			meter := NewLoudnessMeter(rate)
+			meter.Write(sine(1000, -23, 0, 20))
+
+			// EBU Tech 3341, test case 1
+			if l := meter.Integrated(); math.Abs(l+23) > 0.1 {
+				t.Fatalf("loudness is %.2f LUFS, expected -23 LUFS", l)
+			}
+		},
+	)
+
+	t.Run(
+		"should gate silence",
+		func(t *testing.T) {
+			meter := NewLoudnessMeter(rate)
+			meter.Write(sine(1000, -20, 0, 10))
+			meter.Write(make([][2]float64, rate.N(secondsToDuration(10))))
+
+			if l := meter.Integrated(); math.Abs(l+20) > 0.1 {
+				t.Fatalf("loudness is %.2f LUFS, expected -20 LUFS", l)
+			}
+		},
+	)
+
+	t.Run(
+		"should measure silence with -Inf",
+		func(t *testing.T) {
+			meter := NewLoudnessMeter(rate)
+			meter.Write(make([][2]float64, rate.N(secondsToDuration(5))))
+
+			if l := meter.Integrated(); !math.IsInf(l, -1) {
+				t.Fatalf("loudness is %.2f LUFS, expected -Inf", l)
+			}
+
+			if peak := meter.TruePeak(); peak != 0 {
+				t.Fatalf("peak is %f, expected 0", peak)
+			}
+		},
+	)
+
+	t.Run(
+		"should find the peak between the samples",
+		func(t *testing.T) {
+			// an eighth of the sample rate sampled between its peaks, no sample is above 0.924 while the sine peaks at 1
+			samples := sine(float64(rate)/8, 0, math.Pi/8, 1)
+			meter := NewLoudnessMeter(rate)
+			meter.Write(samples)
+
+			for _, sample := range samples {
+				if math.Abs(sample[0]) > 0.924 {
+					t.Fatalf("sample %f is above 0.924", sample[0])
+				}
+			}
+
+			if peak := meter.TruePeak(); peak < 0.99 || peak > 1.01 {
+				t.Fatalf("true peak is %f, expected 1", peak)
+			}
+		},
+	)
+}
+
+func TestLimiter(t *testing.T) {
+	t.Run(
+		"should keep the true peak below the ceiling",
+		func(t *testing.T) {
+			samples := append(sine(1000, -12, 0, 1), sine(3000, 6, 0, 1)...)
+			samples = append(samples, sine(float64(rate)/4, 3, math.Pi/4, 1)...)
+
+			NewLimiter(rate, -1).Process(samples)
+
+			detector := &peakDetector{}
+			detector.Process(samples)
+
+			if peak := ToDecibel(detector.max); peak > -0.9 {
+				t.Fatalf("true peak is %.2f dBTP, expected at most -1 dBTP", peak)
+			}
+		},
+	)
+
+	t.Run(
+		"should delay the signal below the ceiling unchanged",
+		func(t *testing.T) {
+			input := sine(1000, -6, 0, 1)
+			output := make([][2]float64, len(input))
+			copy(output, input)
+
+			limiter := NewLimiter(rate, -1)
+			limiter.Process(output)
+
+			for i := limiter.lookahead; i < len(output); i++ {
+				if output[i] != input[i-limiter.lookahead] {
+					t.Fatalf("sample %d is %v, expected %v", i, output[i], input[i-limiter.lookahead])
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"should release the gain after a peak",
+		func(t *testing.T) {
+			samples := append(sine(1000, 6, 0, 0.1), sine(1000, -6, 0, 2)...)
+			NewLimiter(rate, -1).Process(samples)
+
+			peak := 0.0
+
+			for _, sample := range samples[len(samples)-rate.N(secondsToDuration(0.5)):] {
+				peak = math.Max(peak, math.Abs(sample[0]))
+			}
+
+			if g := ToDecibel(peak); math.Abs(g+6) > 0.1 {
+				t.Fatalf("peak after the release is %.2f dB, expected -6 dB", g)
+			}
+		},
+	)
+}
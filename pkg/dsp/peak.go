@@ -0,0 +1,44 @@
+package dsp
+
+import (
+	"math"
+	"time"
+)
+
+// peakDetector estimates the true peak with a cubic interpolation between the samples (4x oversampling).
+type peakDetector struct {
+	// the last three samples per channel
+	history [2][3]float64
+	max     float64
+}
+
+func (d *peakDetector) Process(samples [][2]float64) {
+	for _, sample := range samples {
+		for c := 0; c < 2; c++ {
+			peak := truePeak(d.history[c][0], d.history[c][1], d.history[c][2], sample[c])
+
+			if peak > d.max {
+				d.max = peak
+			}
+
+			d.history[c][0], d.history[c][1], d.history[c][2] = d.history[c][1], d.history[c][2], sample[c]
+		}
+	}
+}
+
+// truePeak returns the peak between y1 and y2, interpolated with a catmull-rom spline over y0 to y3.
+func truePeak(y0, y1, y2, y3 float64) float64 {
+	peak := math.Max(math.Abs(y1), math.Abs(y2))
+
+	for _, t := range [3]float64{0.25, 0.5, 0.75} {
+		v := 0.5 * (2*y1 + (-y0+y2)*t + (2*y0-5*y1+4*y2-y3)*t*t + (-y0+3*y1-3*y2+y3)*t*t*t)
+
+		peak = math.Max(peak, math.Abs(v))
+	}
+
+	return peak
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
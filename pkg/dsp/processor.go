@@ -0,0 +1,44 @@
+package dsp
+
+import (
+	"github.com/faiface/beep"
+)
+
+// Processor modifies stereo samples in place.
+type Processor interface {
+	Process(samples [][2]float64)
+	Reset()
+}
+
+type processed struct {
+	streamer  beep.Streamer
+	processor Processor
+}
+
+// Apply returns a streamer that runs the processor on the samples of the streamer.
+func Apply(streamer beep.Streamer, processor Processor) beep.Streamer {
+	return &processed{streamer: streamer, processor: processor}
+}
+
+func (p *processed) Stream(samples [][2]float64) (n int, ok bool) {
+	n, ok = p.streamer.Stream(samples)
+	p.processor.Process(samples[:n])
+
+	return n, ok
+}
+
+func (p *processed) Err() error {
+	return p.streamer.Err()
+}
+
+// Gain multiplies the samples with a linear factor.
+type Gain float64
+
+func (g Gain) Process(samples [][2]float64) {
+	for i := range samples {
+		samples[i][0] *= float64(g)
+		samples[i][1] *= float64(g)
+	}
+}
+
+func (g Gain) Reset() {}
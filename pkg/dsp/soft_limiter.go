@@ -0,0 +1,39 @@
+package dsp
+
+import (
+	"math"
+)
+
+// SoftLimiter passes the signal below the threshold and saturates everything above it smoothly towards 0 dBFS.
+type SoftLimiter struct {
+	threshold float64
+}
+
+// NewSoftLimiter creates a soft limiter with the threshold in dBFS.
+func NewSoftLimiter(threshold float64) *SoftLimiter {
+	return &SoftLimiter{threshold: math.Min(FromDecibel(threshold), 1)}
+}
+
+func (l *SoftLimiter) Process(samples [][2]float64) {
+	knee := 1 - l.threshold
+
+	for i := range samples {
+		for c := 0; c < 2; c++ {
+			v := samples[i][c]
+			magnitude := math.Abs(v)
+
+			if magnitude <= l.threshold {
+				continue
+			}
+
+			if knee <= 0 {
+				samples[i][c] = math.Copysign(l.threshold, v)
+				continue
+			}
+
+			samples[i][c] = math.Copysign(l.threshold+knee*math.Tanh((magnitude-l.threshold)/knee), v)
+		}
+	}
+}
+
+func (l *SoftLimiter) Reset() {}
@@ -0,0 +1,32 @@
+package messages
+
+// Frames returns the amount of samples per channel, channels with more samples are truncated to the shortest one.
+func (x *Audio) Frames() int {
+	if len(x.GetChannels()) == 0 {
+		return 0
+	}
+
+	frames := len(x.Channels[0].GetSamples())
+
+	for _, channel := range x.Channels[1:] {
+		if len(channel.GetSamples()) < frames {
+			frames = len(channel.GetSamples())
+		}
+	}
+
+	return frames
+}
+
+// NewAudio creates an audio message from one slice of samples per channel of the layout.
+func NewAudio(layout ChannelLayout, channels ...[]float64) *Audio {
+	audio := &Audio{
+		Layout:   layout,
+		Channels: make([]*Channel, len(channels)),
+	}
+
+	for i, samples := range channels {
+		audio.Channels[i] = &Channel{Samples: samples}
+	}
+
+	return audio
+}
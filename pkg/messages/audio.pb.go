@@ -21,20 +21,126 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ChannelLayout int32
+
+const (
+	ChannelLayout_CHANNEL_LAYOUT_UNSPECIFIED  ChannelLayout = 0
+	ChannelLayout_CHANNEL_LAYOUT_MONO         ChannelLayout = 1
+	ChannelLayout_CHANNEL_LAYOUT_STEREO       ChannelLayout = 2
+	ChannelLayout_CHANNEL_LAYOUT_QUAD         ChannelLayout = 3
+	ChannelLayout_CHANNEL_LAYOUT_SURROUND_5_1 ChannelLayout = 4
+	ChannelLayout_CHANNEL_LAYOUT_SURROUND_7_1 ChannelLayout = 5
+)
+
+// Enum value maps for ChannelLayout.
+var (
+	ChannelLayout_name = map[int32]string{
+		0: "CHANNEL_LAYOUT_UNSPECIFIED",
+		1: "CHANNEL_LAYOUT_MONO",
+		2: "CHANNEL_LAYOUT_STEREO",
+		3: "CHANNEL_LAYOUT_QUAD",
+		4: "CHANNEL_LAYOUT_SURROUND_5_1",
+		5: "CHANNEL_LAYOUT_SURROUND_7_1",
+	}
+	ChannelLayout_value = map[string]int32{
+		"CHANNEL_LAYOUT_UNSPECIFIED":  0,
+		"CHANNEL_LAYOUT_MONO":         1,
+		"CHANNEL_LAYOUT_STEREO":       2,
+		"CHANNEL_LAYOUT_QUAD":         3,
+		"CHANNEL_LAYOUT_SURROUND_5_1": 4,
+		"CHANNEL_LAYOUT_SURROUND_7_1": 5,
+	}
+)
+
+func (x ChannelLayout) Enum() *ChannelLayout {
+	p := new(ChannelLayout)
+	*p = x
+	return p
+}
+
+func (x ChannelLayout) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChannelLayout) Descriptor() protoreflect.EnumDescriptor {
+	return file_audio_proto_enumTypes[0].Descriptor()
+}
+
+func (ChannelLayout) Type() protoreflect.EnumType {
+	return &file_audio_proto_enumTypes[0]
+}
+
+func (x ChannelLayout) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChannelLayout.Descriptor instead.
+func (ChannelLayout) EnumDescriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{0}
+}
+
+type Channel struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Samples []float64 `protobuf:"fixed64,1,rep,packed,name=samples,proto3" json:"samples,omitempty"`
+}
+
+func (x *Channel) Reset() {
+	*x = Channel{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_audio_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Channel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Channel) ProtoMessage() {}
+
+func (x *Channel) ProtoReflect() protoreflect.Message {
+	mi := &file_audio_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Channel.ProtoReflect.Descriptor instead.
+func (*Channel) Descriptor() ([]byte, []int) {
+	return file_audio_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Channel) GetSamples() []float64 {
+	if x != nil {
+		return x.Samples
+	}
+	return nil
+}
+
 type Audio struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Left  []float64              `protobuf:"fixed64,1,rep,packed,name=left,proto3" json:"left,omitempty"`
-	Right []float64              `protobuf:"fixed64,2,rep,packed,name=right,proto3" json:"right,omitempty"`
-	Time  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	Time       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=time,proto3" json:"time,omitempty"`
+	Layout     ChannelLayout          `protobuf:"varint,4,opt,name=layout,proto3,enum=message.ChannelLayout" json:"layout,omitempty"`
+	Channels   []*Channel             `protobuf:"bytes,5,rep,name=channels,proto3" json:"channels,omitempty"`
+	SampleRate uint32                 `protobuf:"varint,6,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"`
 }
 
 func (x *Audio) Reset() {
 	*x = Audio{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_audio_proto_msgTypes[0]
+		mi := &file_audio_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -47,7 +153,7 @@ func (x *Audio) String() string {
 func (*Audio) ProtoMessage() {}
 
 func (x *Audio) ProtoReflect() protoreflect.Message {
-	mi := &file_audio_proto_msgTypes[0]
+	mi := &file_audio_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -60,44 +166,73 @@ func (x *Audio) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Audio.ProtoReflect.Descriptor instead.
 func (*Audio) Descriptor() ([]byte, []int) {
-	return file_audio_proto_rawDescGZIP(), []int{0}
+	return file_audio_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *Audio) GetLeft() []float64 {
+func (x *Audio) GetTime() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Left
+		return x.Time
 	}
 	return nil
 }
 
-func (x *Audio) GetRight() []float64 {
+func (x *Audio) GetLayout() ChannelLayout {
 	if x != nil {
-		return x.Right
+		return x.Layout
 	}
-	return nil
+	return ChannelLayout_CHANNEL_LAYOUT_UNSPECIFIED
 }
 
-func (x *Audio) GetTime() *timestamppb.Timestamp {
+func (x *Audio) GetChannels() []*Channel {
 	if x != nil {
-		return x.Time
+		return x.Channels
 	}
 	return nil
 }
 
+func (x *Audio) GetSampleRate() uint32 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
 var File_audio_proto protoreflect.FileDescriptor
 
 var file_audio_proto_rawDesc = []byte{
 	0x0a, 0x0b, 0x61, 0x75, 0x64, 0x69, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07, 0x6d,
 	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
-	0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x61, 0x0a, 0x05, 0x41, 0x75, 0x64, 0x69, 0x6f,
-	0x12, 0x12, 0x0a, 0x04, 0x6c, 0x65, 0x66, 0x74, 0x18, 0x01, 0x20, 0x03, 0x28, 0x01, 0x52, 0x04,
-	0x6c, 0x65, 0x66, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20,
-	0x03, 0x28, 0x01, 0x52, 0x05, 0x72, 0x69, 0x67, 0x68, 0x74, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69,
-	0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
-	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
-	0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x42, 0x0c, 0x5a, 0x0a, 0x2e, 0x2f,
-	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x23, 0x0a, 0x07, 0x43, 0x68, 0x61, 0x6e, 0x6e,
+	0x65, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x01, 0x52, 0x07, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x73, 0x22, 0xcf, 0x01, 0x0a,
+	0x05, 0x41, 0x75, 0x64, 0x69, 0x6f, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x12, 0x2e, 0x0a, 0x06, 0x6c, 0x61, 0x79, 0x6f, 0x75, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x52, 0x06,
+	0x6c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x12, 0x2c, 0x0a, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x08, 0x63, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x65, 0x5f, 0x72,
+	0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x73, 0x61, 0x6d, 0x70, 0x6c,
+	0x65, 0x52, 0x61, 0x74, 0x65, 0x4a, 0x04, 0x08, 0x01, 0x10, 0x02, 0x4a, 0x04, 0x08, 0x02, 0x10,
+	0x03, 0x52, 0x04, 0x6c, 0x65, 0x66, 0x74, 0x52, 0x05, 0x72, 0x69, 0x67, 0x68, 0x74, 0x2a, 0xbe,
+	0x01, 0x0a, 0x0d, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74,
+	0x12, 0x1e, 0x0a, 0x1a, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x4c, 0x41, 0x59, 0x4f,
+	0x55, 0x54, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00,
+	0x12, 0x17, 0x0a, 0x13, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x4c, 0x41, 0x59, 0x4f,
+	0x55, 0x54, 0x5f, 0x4d, 0x4f, 0x4e, 0x4f, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x43, 0x48, 0x41,
+	0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x4c, 0x41, 0x59, 0x4f, 0x55, 0x54, 0x5f, 0x53, 0x54, 0x45, 0x52,
+	0x45, 0x4f, 0x10, 0x02, 0x12, 0x17, 0x0a, 0x13, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f,
+	0x4c, 0x41, 0x59, 0x4f, 0x55, 0x54, 0x5f, 0x51, 0x55, 0x41, 0x44, 0x10, 0x03, 0x12, 0x1f, 0x0a,
+	0x1b, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x4c, 0x41, 0x59, 0x4f, 0x55, 0x54, 0x5f,
+	0x53, 0x55, 0x52, 0x52, 0x4f, 0x55, 0x4e, 0x44, 0x5f, 0x35, 0x5f, 0x31, 0x10, 0x04, 0x12, 0x1f,
+	0x0a, 0x1b, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x5f, 0x4c, 0x41, 0x59, 0x4f, 0x55, 0x54,
+	0x5f, 0x53, 0x55, 0x52, 0x52, 0x4f, 0x55, 0x4e, 0x44, 0x5f, 0x37, 0x5f, 0x31, 0x10, 0x05, 0x42,
+	0x0c, 0x5a, 0x0a, 0x2e, 0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -112,18 +247,23 @@ func file_audio_proto_rawDescGZIP() []byte {
 	return file_audio_proto_rawDescData
 }
 
-var file_audio_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_audio_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_audio_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_audio_proto_goTypes = []interface{}{
-	(*Audio)(nil),                 // 0: message.Audio
-	(*timestamppb.Timestamp)(nil), // 1: google.protobuf.Timestamp
+	(ChannelLayout)(0),            // 0: message.ChannelLayout
+	(*Channel)(nil),               // 1: message.Channel
+	(*Audio)(nil),                 // 2: message.Audio
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
 }
 var file_audio_proto_depIdxs = []int32{
-	1, // 0: message.Audio.time:type_name -> google.protobuf.Timestamp
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	3, // 0: message.Audio.time:type_name -> google.protobuf.Timestamp
+	0, // 1: message.Audio.layout:type_name -> message.ChannelLayout
+	1, // 2: message.Audio.channels:type_name -> message.Channel
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_audio_proto_init() }
@@ -133,6 +273,18 @@ func file_audio_proto_init() {
 	}
 	if !protoimpl.UnsafeEnabled {
 		file_audio_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Channel); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_audio_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Audio); i {
 			case 0:
 				return &v.state
@@ -150,13 +302,14 @@ func file_audio_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_audio_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   1,
+			NumEnums:      1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_audio_proto_goTypes,
 		DependencyIndexes: file_audio_proto_depIdxs,
+		EnumInfos:         file_audio_proto_enumTypes,
 		MessageInfos:      file_audio_proto_msgTypes,
 	}.Build()
 	File_audio_proto = out.File
@@ -0,0 +1,63 @@
+package messages
+
+import (
+	"io"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Buffer collects input that arrives in arbitrary pieces, e.g. from a stream socket, and decodes the frames in it.
+type Buffer struct {
+	data   []byte
+	offset int
+}
+
+// Write appends the input, it never fails.
+func (b *Buffer) Write(p []byte) (int, error) {
+	// move the unread input to the front once most of the buffer has been read
+	if b.offset > 0 && b.offset >= len(b.data)/2 {
+		b.data = b.data[:copy(b.data, b.data[b.offset:])]
+		b.offset = 0
+	}
+
+	b.data = append(b.data, p...)
+
+	return len(p), nil
+}
+
+// Next decodes the next frame, see Decode.
+func (b *Buffer) Next() (proto.Message, error) {
+	return Decode(b)
+}
+
+func (b *Buffer) InboundBuffered() int {
+	return len(b.data) - b.offset
+}
+
+// Peek returns the next n bytes without consuming them, n <= 0 returns all buffered bytes.
+func (b *Buffer) Peek(n int) ([]byte, error) {
+	if n > b.InboundBuffered() {
+		return nil, io.ErrShortBuffer
+	}
+
+	if n <= 0 {
+		n = b.InboundBuffered()
+	}
+
+	return b.data[b.offset : b.offset+n], nil
+}
+
+func (b *Buffer) Discard(n int) (int, error) {
+	if n > b.InboundBuffered() {
+		n = b.InboundBuffered()
+	}
+
+	b.offset += n
+
+	return n, nil
+}
+
+func (b *Buffer) Reset() {
+	b.data = b.data[:0]
+	b.offset = 0
+}
@@ -0,0 +1,147 @@
+package messages
+
+import (
+	"io"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+// readSize is the amount of input the Decoder reads at once.
+const readSize = 32 * 1024
+
+// Marshal encodes the message as a frame with the DefaultRegistry, e.g. for a datagram.
+func Marshal(message proto.Message) ([]byte, error) {
+	return DefaultRegistry.Marshal(message)
+}
+
+// Marshal encodes the message as a frame. If the Envelope is registered, the message is wrapped in it.
+func (r *Registry) Marshal(message proto.Message) ([]byte, error) {
+	if _, err := r.Code(&Envelope{}); err == nil {
+		envelope, err := Wrap(message)
+
+		if err != nil {
+			return nil, err
+		}
+
+		message = envelope
+	}
+
+	code, err := r.Code(message)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ToBytes(message)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshalling message")
+	}
+
+	return encodeFrame(code, data)
+}
+
+// Unmarshal decodes a single frame with the DefaultRegistry, e.g. a received datagram.
+func Unmarshal(frame []byte) (proto.Message, error) {
+	return DefaultRegistry.Unmarshal(frame)
+}
+
+// Unmarshal decodes a single frame, it fails if frame is not exactly one frame.
+func (r *Registry) Unmarshal(frame []byte) (proto.Message, error) {
+	buffer := &Buffer{data: frame}
+
+	message, err := r.Decode(buffer)
+
+	if errors.Is(err, ErrIncompleteFrame) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if buffer.InboundBuffered() > 0 {
+		return nil, errors.Wrapf(ErrCorruptFrame, "%d trailing bytes", buffer.InboundBuffered())
+	}
+
+	return message, nil
+}
+
+// Encoder writes messages as frames to a writer.
+type Encoder struct {
+	registry *Registry
+	writer   io.Writer
+}
+
+// NewEncoder creates an Encoder with the DefaultRegistry.
+func NewEncoder(w io.Writer) *Encoder {
+	return DefaultRegistry.NewEncoder(w)
+}
+
+func (r *Registry) NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{registry: r, writer: w}
+}
+
+// Encode writes the message as one frame with a single write.
+func (e *Encoder) Encode(message proto.Message) error {
+	frame, err := e.registry.Marshal(message)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = e.writer.Write(frame)
+
+	return err
+}
+
+// Decoder reads frames from a reader, e.g. a file or a stream socket.
+type Decoder struct {
+	registry *Registry
+	reader   io.Reader
+	buffer   *Buffer
+	chunk    []byte
+
+	// the error of the reader, it is returned once the buffered frames are decoded
+	err error
+}
+
+// NewDecoder creates a Decoder with the DefaultRegistry.
+func NewDecoder(r io.Reader) *Decoder {
+	return DefaultRegistry.NewDecoder(r)
+}
+
+func (r *Registry) NewDecoder(reader io.Reader) *Decoder {
+	return &Decoder{
+		registry: r,
+		reader:   reader,
+		buffer:   &Buffer{},
+		chunk:    make([]byte, readSize),
+	}
+}
+
+// Decode reads the next message. It returns io.EOF at the end of the input and io.ErrUnexpectedEOF if the input
+// ends within a frame. Like Registry.Decode, corrupt or unknown frames are skipped, so decoding can continue after
+// errors other than those of the reader.
+func (d *Decoder) Decode() (proto.Message, error) {
+	for {
+		message, err := d.registry.Decode(d.buffer)
+
+		if !errors.Is(err, ErrIncompleteFrame) {
+			return message, err
+		}
+
+		if d.err != nil {
+			if d.err == io.EOF && d.buffer.InboundBuffered() > 0 {
+				return nil, io.ErrUnexpectedEOF
+			}
+
+			return nil, d.err
+		}
+
+		n, err := d.reader.Read(d.chunk)
+		d.buffer.Write(d.chunk[:n])
+		d.err = err
+	}
+}
@@ -0,0 +1,133 @@
+package messages
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCodec(t *testing.T) {
+	t.Run(
+		"should decode the encoded messages from a reader",
+		func(t *testing.T) {
+			expected := testMessages()
+			reader, writer := io.Pipe()
+
+			go func() {
+				encoder := NewEncoder(writer)
+
+				for _, message := range expected {
+					err := encoder.Encode(message)
+
+					if err != nil {
+						writer.CloseWithError(err)
+						return
+					}
+				}
+
+				writer.Close()
+			}()
+
+			decoder := NewDecoder(reader)
+
+			for i, message := range expected {
+				decoded, err := decoder.Decode()
+
+				if err != nil {
+					t.Fatalf("message %d: %v", i, err)
+				}
+
+				if !proto.Equal(message, decoded) {
+					t.Errorf("message %d: expected %v, got %v", i, message, decoded)
+				}
+			}
+
+			_, err := decoder.Decode()
+
+			if err != io.EOF {
+				t.Errorf("expected io.EOF, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"should report a frame cut off by the end of the input",
+		func(t *testing.T) {
+			frame, err := Marshal(testMessages()[0])
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = NewDecoder(bytes.NewReader(frame[:len(frame)-1])).Decode()
+
+			if err != io.ErrUnexpectedEOF {
+				t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"should unmarshal exactly one frame",
+		func(t *testing.T) {
+			message := testMessages()[1]
+			frame, err := Marshal(message)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			decoded, err := Unmarshal(frame)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !proto.Equal(message, decoded) {
+				t.Errorf("expected %v, got %v", message, decoded)
+			}
+
+			_, err = Unmarshal(append(frame, 0))
+
+			if !errors.Is(err, ErrCorruptFrame) {
+				t.Errorf("expected ErrCorruptFrame for trailing bytes, got %v", err)
+			}
+		},
+	)
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+
+	err := registry.Register(0x90, &Time{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := registry.Register(0x90, &Latency{}); err == nil {
+		t.Error("expected an error for a registered code")
+	}
+
+	if err := registry.Register(0x91, &Time{}); err == nil {
+		t.Error("expected an error for a registered message")
+	}
+
+	if _, err := registry.Marshal(&Latency{}); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType, got %v", err)
+	}
+
+	frame, err := registry.Marshal(&Time{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the default registry has no message with the code
+	if _, err := Unmarshal(frame); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("expected ErrUnsupportedType, got %v", err)
+	}
+}
@@ -0,0 +1,264 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.19.4
+// source: dsp_config.proto
+
+package messages
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type EqualizerBand struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Frequency float64 `protobuf:"fixed64,1,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	Gain      float64 `protobuf:"fixed64,2,opt,name=gain,proto3" json:"gain,omitempty"`
+	Q         float64 `protobuf:"fixed64,3,opt,name=q,proto3" json:"q,omitempty"`
+}
+
+func (x *EqualizerBand) Reset() {
+	*x = EqualizerBand{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dsp_config_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EqualizerBand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EqualizerBand) ProtoMessage() {}
+
+func (x *EqualizerBand) ProtoReflect() protoreflect.Message {
+	mi := &file_dsp_config_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EqualizerBand.ProtoReflect.Descriptor instead.
+func (*EqualizerBand) Descriptor() ([]byte, []int) {
+	return file_dsp_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *EqualizerBand) GetFrequency() float64 {
+	if x != nil {
+		return x.Frequency
+	}
+	return 0
+}
+
+func (x *EqualizerBand) GetGain() float64 {
+	if x != nil {
+		return x.Gain
+	}
+	return 0
+}
+
+func (x *EqualizerBand) GetQ() float64 {
+	if x != nil {
+		return x.Q
+	}
+	return 0
+}
+
+type DspConfig struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Bands    []*EqualizerBand `protobuf:"bytes,1,rep,name=bands,proto3" json:"bands,omitempty"`
+	Bass     float64          `protobuf:"fixed64,2,opt,name=bass,proto3" json:"bass,omitempty"`
+	Treble   float64          `protobuf:"fixed64,3,opt,name=treble,proto3" json:"treble,omitempty"`
+	HighPass float64          `protobuf:"fixed64,4,opt,name=high_pass,json=highPass,proto3" json:"high_pass,omitempty"`
+	Limiter  float64          `protobuf:"fixed64,5,opt,name=limiter,proto3" json:"limiter,omitempty"`
+}
+
+func (x *DspConfig) Reset() {
+	*x = DspConfig{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_dsp_config_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DspConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DspConfig) ProtoMessage() {}
+
+func (x *DspConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_dsp_config_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DspConfig.ProtoReflect.Descriptor instead.
+func (*DspConfig) Descriptor() ([]byte, []int) {
+	return file_dsp_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DspConfig) GetBands() []*EqualizerBand {
+	if x != nil {
+		return x.Bands
+	}
+	return nil
+}
+
+func (x *DspConfig) GetBass() float64 {
+	if x != nil {
+		return x.Bass
+	}
+	return 0
+}
+
+func (x *DspConfig) GetTreble() float64 {
+	if x != nil {
+		return x.Treble
+	}
+	return 0
+}
+
+func (x *DspConfig) GetHighPass() float64 {
+	if x != nil {
+		return x.HighPass
+	}
+	return 0
+}
+
+func (x *DspConfig) GetLimiter() float64 {
+	if x != nil {
+		return x.Limiter
+	}
+	return 0
+}
+
+var File_dsp_config_proto protoreflect.FileDescriptor
+
+var file_dsp_config_proto_rawDesc = []byte{
+	0x0a, 0x10, 0x64, 0x73, 0x70, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x12, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x4f, 0x0a, 0x0d, 0x45,
+	0x71, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x42, 0x61, 0x6e, 0x64, 0x12, 0x1c, 0x0a, 0x09,
+	0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x09, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x67, 0x61,
+	0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x67, 0x61, 0x69, 0x6e, 0x12, 0x0c,
+	0x0a, 0x01, 0x71, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x01, 0x71, 0x22, 0x9c, 0x01, 0x0a,
+	0x09, 0x44, 0x73, 0x70, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x2c, 0x0a, 0x05, 0x62, 0x61,
+	0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x2e, 0x45, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x72, 0x42, 0x61, 0x6e,
+	0x64, 0x52, 0x05, 0x62, 0x61, 0x6e, 0x64, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x61, 0x73, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x62, 0x61, 0x73, 0x73, 0x12, 0x16, 0x0a, 0x06,
+	0x74, 0x72, 0x65, 0x62, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x74, 0x72,
+	0x65, 0x62, 0x6c, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x68, 0x69, 0x67, 0x68, 0x5f, 0x70, 0x61, 0x73,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x68, 0x69, 0x67, 0x68, 0x50, 0x61, 0x73,
+	0x73, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x07, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x65, 0x72, 0x42, 0x0c, 0x5a, 0x0a, 0x2e,
+	0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_dsp_config_proto_rawDescOnce sync.Once
+	file_dsp_config_proto_rawDescData = file_dsp_config_proto_rawDesc
+)
+
+func file_dsp_config_proto_rawDescGZIP() []byte {
+	file_dsp_config_proto_rawDescOnce.Do(func() {
+		file_dsp_config_proto_rawDescData = protoimpl.X.CompressGZIP(file_dsp_config_proto_rawDescData)
+	})
+	return file_dsp_config_proto_rawDescData
+}
+
+var file_dsp_config_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_dsp_config_proto_goTypes = []interface{}{
+	(*EqualizerBand)(nil), // 0: message.EqualizerBand
+	(*DspConfig)(nil),     // 1: message.DspConfig
+}
+var file_dsp_config_proto_depIdxs = []int32{
+	0, // 0: message.DspConfig.bands:type_name -> message.EqualizerBand
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_dsp_config_proto_init() }
+func file_dsp_config_proto_init() {
+	if File_dsp_config_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_dsp_config_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EqualizerBand); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_dsp_config_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DspConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_dsp_config_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_dsp_config_proto_goTypes,
+		DependencyIndexes: file_dsp_config_proto_depIdxs,
+		MessageInfos:      file_dsp_config_proto_msgTypes,
+	}.Build()
+	File_dsp_config_proto = out.File
+	file_dsp_config_proto_rawDesc = nil
+	file_dsp_config_proto_goTypes = nil
+	file_dsp_config_proto_depIdxs = nil
+}
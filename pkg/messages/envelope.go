@@ -0,0 +1,74 @@
+package messages
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// EnvelopeType is the code of the Envelope, all messages are sent wrapped in it. The oneof of the Envelope is the
+// dispatch of the protocol, new messages get a field in it.
+const EnvelopeType = 0x01
+
+var (
+	// envelopeFields maps the messages to their field in the oneof of the Envelope, it is built on first use
+	// as the generated descriptors are not ready during package initialization
+	envelopeFields     map[protoreflect.FullName]protoreflect.FieldDescriptor
+	envelopeFieldsOnce = &sync.Once{}
+)
+
+func envelopeField(name protoreflect.FullName) (protoreflect.FieldDescriptor, bool) {
+	envelopeFieldsOnce.Do(
+		func() {
+			envelopeFields = map[protoreflect.FullName]protoreflect.FieldDescriptor{}
+			fields := (&Envelope{}).ProtoReflect().Descriptor().Oneofs().ByName("message").Fields()
+
+			for i := 0; i < fields.Len(); i++ {
+				field := fields.Get(i)
+				envelopeFields[field.Message().FullName()] = field
+			}
+		},
+	)
+
+	field, ok := envelopeFields[name]
+
+	return field, ok
+}
+
+// Wrap puts the message into an Envelope, it fails with ErrUnsupportedType for messages without a field in the Envelope.
+func Wrap(message proto.Message) (*Envelope, error) {
+	if envelope, ok := message.(*Envelope); ok {
+		return envelope, nil
+	}
+
+	reflected := message.ProtoReflect()
+	field, ok := envelopeField(reflected.Descriptor().FullName())
+
+	if !ok {
+		return nil, errors.Wrapf(ErrUnsupportedType, "%s has no envelope field", reflected.Descriptor().FullName())
+	}
+
+	envelope := &Envelope{}
+	envelope.ProtoReflect().Set(field, protoreflect.ValueOfMessage(reflected))
+
+	return envelope, nil
+}
+
+// Unwrap returns the message of the envelope. Envelopes with an unknown field, e.g. from a newer peer, or without
+// a message fail with ErrUnsupportedType.
+func (x *Envelope) Unwrap() (proto.Message, error) {
+	reflected := x.ProtoReflect()
+	field := reflected.WhichOneof(reflected.Descriptor().Oneofs().ByName("message"))
+
+	if field == nil {
+		if len(reflected.GetUnknown()) > 0 {
+			return nil, errors.Wrap(ErrUnsupportedType, "envelope with an unknown message")
+		}
+
+		return nil, errors.Wrap(ErrUnsupportedType, "empty envelope")
+	}
+
+	return reflected.Get(field).Message().Interface(), nil
+}
@@ -0,0 +1,394 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.19.4
+// source: envelope.proto
+
+package messages
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Envelope struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Message:
+	//	*Envelope_Audio
+	//	*Envelope_Time
+	//	*Envelope_Latency
+	//	*Envelope_Command
+	//	*Envelope_OutputLatency
+	//	*Envelope_Calibration
+	//	*Envelope_CalibrationResult
+	//	*Envelope_NowPlaying
+	//	*Envelope_PlaybackState
+	//	*Envelope_Subscription
+	//	*Envelope_DspConfig
+	Message isEnvelope_Message `protobuf_oneof:"message"`
+}
+
+func (x *Envelope) Reset() {
+	*x = Envelope{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_envelope_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Envelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Envelope) ProtoMessage() {}
+
+func (x *Envelope) ProtoReflect() protoreflect.Message {
+	mi := &file_envelope_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Envelope.ProtoReflect.Descriptor instead.
+func (*Envelope) Descriptor() ([]byte, []int) {
+	return file_envelope_proto_rawDescGZIP(), []int{0}
+}
+
+func (m *Envelope) GetMessage() isEnvelope_Message {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
+func (x *Envelope) GetAudio() *Audio {
+	if x, ok := x.GetMessage().(*Envelope_Audio); ok {
+		return x.Audio
+	}
+	return nil
+}
+
+func (x *Envelope) GetTime() *Time {
+	if x, ok := x.GetMessage().(*Envelope_Time); ok {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *Envelope) GetLatency() *Latency {
+	if x, ok := x.GetMessage().(*Envelope_Latency); ok {
+		return x.Latency
+	}
+	return nil
+}
+
+func (x *Envelope) GetCommand() *Command {
+	if x, ok := x.GetMessage().(*Envelope_Command); ok {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *Envelope) GetOutputLatency() *OutputLatency {
+	if x, ok := x.GetMessage().(*Envelope_OutputLatency); ok {
+		return x.OutputLatency
+	}
+	return nil
+}
+
+func (x *Envelope) GetCalibration() *Calibration {
+	if x, ok := x.GetMessage().(*Envelope_Calibration); ok {
+		return x.Calibration
+	}
+	return nil
+}
+
+func (x *Envelope) GetCalibrationResult() *CalibrationResult {
+	if x, ok := x.GetMessage().(*Envelope_CalibrationResult); ok {
+		return x.CalibrationResult
+	}
+	return nil
+}
+
+func (x *Envelope) GetNowPlaying() *NowPlaying {
+	if x, ok := x.GetMessage().(*Envelope_NowPlaying); ok {
+		return x.NowPlaying
+	}
+	return nil
+}
+
+func (x *Envelope) GetPlaybackState() *PlaybackState {
+	if x, ok := x.GetMessage().(*Envelope_PlaybackState); ok {
+		return x.PlaybackState
+	}
+	return nil
+}
+
+func (x *Envelope) GetSubscription() *Subscription {
+	if x, ok := x.GetMessage().(*Envelope_Subscription); ok {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *Envelope) GetDspConfig() *DspConfig {
+	if x, ok := x.GetMessage().(*Envelope_DspConfig); ok {
+		return x.DspConfig
+	}
+	return nil
+}
+
+type isEnvelope_Message interface {
+	isEnvelope_Message()
+}
+
+type Envelope_Audio struct {
+	Audio *Audio `protobuf:"bytes,1,opt,name=audio,proto3,oneof"`
+}
+
+type Envelope_Time struct {
+	Time *Time `protobuf:"bytes,2,opt,name=time,proto3,oneof"`
+}
+
+type Envelope_Latency struct {
+	Latency *Latency `protobuf:"bytes,3,opt,name=latency,proto3,oneof"`
+}
+
+type Envelope_Command struct {
+	Command *Command `protobuf:"bytes,4,opt,name=command,proto3,oneof"`
+}
+
+type Envelope_OutputLatency struct {
+	OutputLatency *OutputLatency `protobuf:"bytes,5,opt,name=output_latency,json=outputLatency,proto3,oneof"`
+}
+
+type Envelope_Calibration struct {
+	Calibration *Calibration `protobuf:"bytes,6,opt,name=calibration,proto3,oneof"`
+}
+
+type Envelope_CalibrationResult struct {
+	CalibrationResult *CalibrationResult `protobuf:"bytes,7,opt,name=calibration_result,json=calibrationResult,proto3,oneof"`
+}
+
+type Envelope_NowPlaying struct {
+	NowPlaying *NowPlaying `protobuf:"bytes,8,opt,name=now_playing,json=nowPlaying,proto3,oneof"`
+}
+
+type Envelope_PlaybackState struct {
+	PlaybackState *PlaybackState `protobuf:"bytes,9,opt,name=playback_state,json=playbackState,proto3,oneof"`
+}
+
+type Envelope_Subscription struct {
+	Subscription *Subscription `protobuf:"bytes,10,opt,name=subscription,proto3,oneof"`
+}
+
+type Envelope_DspConfig struct {
+	DspConfig *DspConfig `protobuf:"bytes,11,opt,name=dsp_config,json=dspConfig,proto3,oneof"`
+}
+
+func (*Envelope_Audio) isEnvelope_Message() {}
+
+func (*Envelope_Time) isEnvelope_Message() {}
+
+func (*Envelope_Latency) isEnvelope_Message() {}
+
+func (*Envelope_Command) isEnvelope_Message() {}
+
+func (*Envelope_OutputLatency) isEnvelope_Message() {}
+
+func (*Envelope_Calibration) isEnvelope_Message() {}
+
+func (*Envelope_CalibrationResult) isEnvelope_Message() {}
+
+func (*Envelope_NowPlaying) isEnvelope_Message() {}
+
+func (*Envelope_PlaybackState) isEnvelope_Message() {}
+
+func (*Envelope_Subscription) isEnvelope_Message() {}
+
+func (*Envelope_DspConfig) isEnvelope_Message() {}
+
+var File_envelope_proto protoreflect.FileDescriptor
+
+var file_envelope_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x65, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x0b, 0x61, 0x75, 0x64, 0x69, 0x6f,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x1a, 0x0d, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x0d, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x14, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x11, 0x63, 0x61, 0x6c, 0x69, 0x62, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x11, 0x6e, 0x6f, 0x77, 0x5f, 0x70,
+	0x6c, 0x61, 0x79, 0x69, 0x6e, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x14, 0x70, 0x6c,
+	0x61, 0x79, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x1a, 0x12, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x10, 0x64, 0x73, 0x70, 0x5f, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf1, 0x04, 0x0a, 0x08, 0x45, 0x6e, 0x76,
+	0x65, 0x6c, 0x6f, 0x70, 0x65, 0x12, 0x26, 0x0a, 0x05, 0x61, 0x75, 0x64, 0x69, 0x6f, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x41,
+	0x75, 0x64, 0x69, 0x6f, 0x48, 0x00, 0x52, 0x05, 0x61, 0x75, 0x64, 0x69, 0x6f, 0x12, 0x23, 0x0a,
+	0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x48, 0x00, 0x52, 0x04, 0x74, 0x69,
+	0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x4c, 0x61,
+	0x74, 0x65, 0x6e, 0x63, 0x79, 0x48, 0x00, 0x52, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79,
+	0x12, 0x2c, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x43, 0x6f, 0x6d, 0x6d,
+	0x61, 0x6e, 0x64, 0x48, 0x00, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x3f,
+	0x0a, 0x0e, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x48, 0x00,
+	0x52, 0x0d, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x12,
+	0x38, 0x0a, 0x0b, 0x63, 0x61, 0x6c, 0x69, 0x62, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x43,
+	0x61, 0x6c, 0x69, 0x62, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0b, 0x63, 0x61,
+	0x6c, 0x69, 0x62, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x4b, 0x0a, 0x12, 0x63, 0x61, 0x6c,
+	0x69, 0x62, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e,
+	0x43, 0x61, 0x6c, 0x69, 0x62, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x48, 0x00, 0x52, 0x11, 0x63, 0x61, 0x6c, 0x69, 0x62, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x36, 0x0a, 0x0b, 0x6e, 0x6f, 0x77, 0x5f, 0x70, 0x6c,
+	0x61, 0x79, 0x69, 0x6e, 0x67, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x4e, 0x6f, 0x77, 0x50, 0x6c, 0x61, 0x79, 0x69, 0x6e, 0x67,
+	0x48, 0x00, 0x52, 0x0a, 0x6e, 0x6f, 0x77, 0x50, 0x6c, 0x61, 0x79, 0x69, 0x6e, 0x67, 0x12, 0x3f,
+	0x0a, 0x0e, 0x70, 0x6c, 0x61, 0x79, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x2e, 0x50, 0x6c, 0x61, 0x79, 0x62, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x65, 0x48, 0x00,
+	0x52, 0x0d, 0x70, 0x6c, 0x61, 0x79, 0x62, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12,
+	0x3b, 0x0a, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e,
+	0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0c,
+	0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x33, 0x0a, 0x0a,
+	0x64, 0x73, 0x70, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x44, 0x73, 0x70, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x48, 0x00, 0x52, 0x09, 0x64, 0x73, 0x70, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x42, 0x09, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x42, 0x0c, 0x5a, 0x0a,
+	0x2e, 0x2f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_envelope_proto_rawDescOnce sync.Once
+	file_envelope_proto_rawDescData = file_envelope_proto_rawDesc
+)
+
+func file_envelope_proto_rawDescGZIP() []byte {
+	file_envelope_proto_rawDescOnce.Do(func() {
+		file_envelope_proto_rawDescData = protoimpl.X.CompressGZIP(file_envelope_proto_rawDescData)
+	})
+	return file_envelope_proto_rawDescData
+}
+
+var file_envelope_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_envelope_proto_goTypes = []interface{}{
+	(*Envelope)(nil),          // 0: message.Envelope
+	(*Audio)(nil),             // 1: message.Audio
+	(*Time)(nil),              // 2: message.Time
+	(*Latency)(nil),           // 3: message.Latency
+	(*Command)(nil),           // 4: message.Command
+	(*OutputLatency)(nil),     // 5: message.OutputLatency
+	(*Calibration)(nil),       // 6: message.Calibration
+	(*CalibrationResult)(nil), // 7: message.CalibrationResult
+	(*NowPlaying)(nil),        // 8: message.NowPlaying
+	(*PlaybackState)(nil),     // 9: message.PlaybackState
+	(*Subscription)(nil),      // 10: message.Subscription
+	(*DspConfig)(nil),         // 11: message.DspConfig
+}
+var file_envelope_proto_depIdxs = []int32{
+	1,  // 0: message.Envelope.audio:type_name -> message.Audio
+	2,  // 1: message.Envelope.time:type_name -> message.Time
+	3,  // 2: message.Envelope.latency:type_name -> message.Latency
+	4,  // 3: message.Envelope.command:type_name -> message.Command
+	5,  // 4: message.Envelope.output_latency:type_name -> message.OutputLatency
+	6,  // 5: message.Envelope.calibration:type_name -> message.Calibration
+	7,  // 6: message.Envelope.calibration_result:type_name -> message.CalibrationResult
+	8,  // 7: message.Envelope.now_playing:type_name -> message.NowPlaying
+	9,  // 8: message.Envelope.playback_state:type_name -> message.PlaybackState
+	10, // 9: message.Envelope.subscription:type_name -> message.Subscription
+	11, // 10: message.Envelope.dsp_config:type_name -> message.DspConfig
+	11, // [11:11] is the sub-list for method output_type
+	11, // [11:11] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_envelope_proto_init() }
+func file_envelope_proto_init() {
+	if File_envelope_proto != nil {
+		return
+	}
+	file_audio_proto_init()
+	file_time_proto_init()
+	file_latency_proto_init()
+	file_command_proto_init()
+	file_output_latency_proto_init()
+	file_calibration_proto_init()
+	file_now_playing_proto_init()
+	file_playback_state_proto_init()
+	file_subscription_proto_init()
+	file_dsp_config_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_envelope_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Envelope); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_envelope_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*Envelope_Audio)(nil),
+		(*Envelope_Time)(nil),
+		(*Envelope_Latency)(nil),
+		(*Envelope_Command)(nil),
+		(*Envelope_OutputLatency)(nil),
+		(*Envelope_Calibration)(nil),
+		(*Envelope_CalibrationResult)(nil),
+		(*Envelope_NowPlaying)(nil),
+		(*Envelope_PlaybackState)(nil),
+		(*Envelope_Subscription)(nil),
+		(*Envelope_DspConfig)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_envelope_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_envelope_proto_goTypes,
+		DependencyIndexes: file_envelope_proto_depIdxs,
+		MessageInfos:      file_envelope_proto_msgTypes,
+	}.Build()
+	File_envelope_proto = out.File
+	file_envelope_proto_rawDesc = nil
+	file_envelope_proto_goTypes = nil
+	file_envelope_proto_depIdxs = nil
+}
@@ -0,0 +1,92 @@
+package messages
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestEnvelope(t *testing.T) {
+	t.Run(
+		"should unwrap the wrapped messages",
+		func(t *testing.T) {
+			for _, message := range append(testMessages(), &Command{Name: "stop"}) {
+				envelope, err := Wrap(message)
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				unwrapped, err := envelope.Unwrap()
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if unwrapped != message {
+					t.Errorf("expected %v, got %v", message, unwrapped)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"should not wrap messages without a field",
+		func(t *testing.T) {
+			_, err := Wrap(&Channel{})
+
+			if !errors.Is(err, ErrUnsupportedType) {
+				t.Errorf("expected ErrUnsupportedType, got %v", err)
+			}
+
+			_, err = ToPacket(&Channel{}).Bytes()
+
+			if !errors.Is(err, ErrUnsupportedType) {
+				t.Errorf("expected ErrUnsupportedType, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"should skip a message unknown to the envelope",
+		func(t *testing.T) {
+			// an envelope of a newer peer with a message in field 99
+			data := protowire.AppendTag(nil, 99, protowire.BytesType)
+			data = protowire.AppendBytes(data, []byte{0x08, 0x01})
+
+			frame, err := encodeFrame(EnvelopeType, data)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			next, err := Marshal(&Latency{Latency: 42})
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			buffer := &Buffer{}
+			buffer.Write(frame)
+			buffer.Write(next)
+
+			_, err = buffer.Next()
+
+			if !errors.Is(err, ErrUnsupportedType) {
+				t.Errorf("expected ErrUnsupportedType, got %v", err)
+			}
+
+			message, err := buffer.Next()
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !proto.Equal(message, &Latency{Latency: 42}) {
+				t.Errorf("expected the latency, got %v", message)
+			}
+		},
+	)
+}
@@ -0,0 +1,121 @@
+package messages
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+
+	"github.com/pkg/errors"
+)
+
+// Frame header
+//
+// Format:
+// ------------------------------------------------------------------------------------------------
+// | Magic   | Version | Flags  | Message Type | Length  | Header CRC32 | Message CRC32 | Message |
+// ------------------------------------------------------------------------------------------------
+// | 4 bytes | 1 byte  | 1 byte | 2 bytes      | 4 bytes | 4 bytes      | 4 bytes       | N bytes |
+// ------------------------------------------------------------------------------------------------
+// | "NAUD"  | uint8   | uint8  | uint16       | uint32  | uint32       | uint32        | []byte  |
+// ------------------------------------------------------------------------------------------------
+//
+// All numbers are big endian. The header CRC32 (IEEE) covers the first 12 bytes of the header, so a corrupt length is
+// detected before waiting for the message. The message CRC32 covers the message. The flags are reserved and must be 0,
+// frames with other flags are skipped like frames of other versions.
+const (
+	HeaderSize = 20
+
+	// Version of the frame format, frames of other versions are skipped
+	Version = 2
+
+	// MaxFrameSize limits the message of a frame, it leaves room for cover art in NowPlaying
+	MaxFrameSize = 4 * 1024 * 1024
+)
+
+// Magic marks the start of every frame, it is used to find the next frame after corrupt input.
+var Magic = []byte("NAUD")
+
+var (
+	ErrCorruptFrame       = errors.New("corrupt frame")
+	ErrFrameTooLarge      = errors.New("frame too large")
+	ErrUnsupportedVersion = errors.New("unsupported frame version")
+	ErrUnsupportedFlags   = errors.New("unsupported frame flags")
+	ErrUnsupportedType    = errors.New("unsupported message type")
+)
+
+// header is the decoded frame header.
+type header struct {
+	version  uint8
+	flags    uint8
+	mtype    int
+	length   int
+	checksum uint32
+}
+
+// headerChecksumSize is the part of the header covered by the header checksum.
+const headerChecksumSize = 12
+
+// encodeFrame prefixes the message with the header.
+func encodeFrame(mtype int, message []byte) ([]byte, error) {
+	if len(message) > MaxFrameSize {
+		return nil, errors.Wrapf(ErrFrameTooLarge, "%d bytes", len(message))
+	}
+
+	frame := make([]byte, HeaderSize+len(message))
+
+	copy(frame[0:4], Magic)
+	frame[4] = Version
+	frame[5] = 0
+	binary.BigEndian.PutUint16(frame[6:8], uint16(mtype))
+	binary.BigEndian.PutUint32(frame[8:12], uint32(len(message)))
+	binary.BigEndian.PutUint32(frame[12:16], crc32.ChecksumIEEE(frame[0:headerChecksumSize]))
+	binary.BigEndian.PutUint32(frame[16:20], crc32.ChecksumIEEE(message))
+	copy(frame[HeaderSize:], message)
+
+	return frame, nil
+}
+
+// parseHeader decodes the header at the start of b, it fails with ErrCorruptFrame if b does not start with the magic
+// or the header checksum does not match, and with ErrFrameTooLarge if the length exceeds MaxFrameSize.
+func parseHeader(b []byte) (header, error) {
+	if !bytes.Equal(b[0:4], Magic) {
+		return header{}, ErrCorruptFrame
+	}
+
+	if crc32.ChecksumIEEE(b[0:headerChecksumSize]) != binary.BigEndian.Uint32(b[12:16]) {
+		return header{}, errors.Wrap(ErrCorruptFrame, "header checksum mismatch")
+	}
+
+	h := header{
+		version:  b[4],
+		flags:    b[5],
+		mtype:    int(binary.BigEndian.Uint16(b[6:8])),
+		length:   int(binary.BigEndian.Uint32(b[8:12])),
+		checksum: binary.BigEndian.Uint32(b[16:20]),
+	}
+
+	if h.length > MaxFrameSize {
+		return h, errors.Wrapf(ErrFrameTooLarge, "%d bytes", h.length)
+	}
+
+	return h, nil
+}
+
+// resyncOffset returns the position of the next possible frame start in b after its first byte,
+// or the position from which a magic could still start with the next bytes.
+func resyncOffset(b []byte) int {
+	index := bytes.Index(b[1:], Magic)
+
+	if index >= 0 {
+		return index + 1
+	}
+
+	// keep a partial magic at the end
+	for offset := len(b) - len(Magic) + 1; offset < len(b); offset++ {
+		if offset > 0 && bytes.HasPrefix(Magic, b[offset:]) {
+			return offset
+		}
+	}
+
+	return len(b)
+}
@@ -4,30 +4,9 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-// Codes for the different types of messages.
-const (
-	AudioType   = 0x10
-	TimeType    = 0x20
-	LatencyType = 0x30
-)
-
+// ToPacket wraps the message to be sent, unsupported messages fail in Packet.Bytes.
 func ToPacket(message proto.Message) *Packet {
-	packet := &Packet{}
-
-	switch message.(type) {
-	case *Audio:
-		packet.mtype = AudioType
-	case *Time:
-		packet.mtype = TimeType
-	case *Latency:
-		packet.mtype = LatencyType
-	default:
-		panic("unsupported message type")
-	}
-
-	packet.message = message
-
-	return packet
+	return &Packet{message: message}
 }
 
 // ToBytes marshals a messageChan to a buffer. The first byte of the buffer is the messageChan type.
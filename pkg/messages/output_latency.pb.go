@@ -0,0 +1,161 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.19.4
+// source: output_latency.proto
+
+package messages
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type OutputLatency struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Latency  int64  `protobuf:"varint,1,opt,name=latency,proto3" json:"latency,omitempty"`
+	Relative bool   `protobuf:"varint,2,opt,name=relative,proto3" json:"relative,omitempty"`
+	Host     string `protobuf:"bytes,3,opt,name=host,proto3" json:"host,omitempty"`
+}
+
+func (x *OutputLatency) Reset() {
+	*x = OutputLatency{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_output_latency_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *OutputLatency) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutputLatency) ProtoMessage() {}
+
+func (x *OutputLatency) ProtoReflect() protoreflect.Message {
+	mi := &file_output_latency_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutputLatency.ProtoReflect.Descriptor instead.
+func (*OutputLatency) Descriptor() ([]byte, []int) {
+	return file_output_latency_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OutputLatency) GetLatency() int64 {
+	if x != nil {
+		return x.Latency
+	}
+	return 0
+}
+
+func (x *OutputLatency) GetRelative() bool {
+	if x != nil {
+		return x.Relative
+	}
+	return false
+}
+
+func (x *OutputLatency) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+var File_output_latency_proto protoreflect.FileDescriptor
+
+var file_output_latency_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22,
+	0x59, 0x0a, 0x0d, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79,
+	0x12, 0x18, 0x0a, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65,
+	0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65,
+	0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74, 0x42, 0x0c, 0x5a, 0x0a, 0x2e, 0x2f,
+	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_output_latency_proto_rawDescOnce sync.Once
+	file_output_latency_proto_rawDescData = file_output_latency_proto_rawDesc
+)
+
+func file_output_latency_proto_rawDescGZIP() []byte {
+	file_output_latency_proto_rawDescOnce.Do(func() {
+		file_output_latency_proto_rawDescData = protoimpl.X.CompressGZIP(file_output_latency_proto_rawDescData)
+	})
+	return file_output_latency_proto_rawDescData
+}
+
+var file_output_latency_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_output_latency_proto_goTypes = []interface{}{
+	(*OutputLatency)(nil), // 0: message.OutputLatency
+}
+var file_output_latency_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_output_latency_proto_init() }
+func file_output_latency_proto_init() {
+	if File_output_latency_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_output_latency_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*OutputLatency); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_output_latency_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_output_latency_proto_goTypes,
+		DependencyIndexes: file_output_latency_proto_depIdxs,
+		MessageInfos:      file_output_latency_proto_msgTypes,
+	}.Build()
+	File_output_latency_proto = out.File
+	file_output_latency_proto_rawDesc = nil
+	file_output_latency_proto_goTypes = nil
+	file_output_latency_proto_depIdxs = nil
+}
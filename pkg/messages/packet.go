@@ -1,151 +1,123 @@
 package messages
 
 import (
-	"encoding/binary"
 	"fmt"
+	"hash/crc32"
 
 	"github.com/panjf2000/gnet/v2"
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/proto"
 )
 
-// Packet is a wrapper around a byte array.
-//
-// Format:
-// -----------------------------------
-// | Message Type | Length  | Message |
-// ------------------------------------
-// |   8 bytes    | 8 bytes | N bytes |
-// ------------------------------------
-// |	int       | uint64  |  []byte |
-// ------------------------------------
-//
+// ErrIncompleteFrame is returned while the source has not buffered the whole next frame yet.
+var ErrIncompleteFrame = errors.New("incomplete frame")
+
+// Packet is a message that is sent as frame in an Envelope, see HeaderSize for the format.
 type Packet struct {
-	mtype   int
 	message proto.Message
 }
 
-func FromConnection(connection gnet.Conn) (proto.Message, error) {
-	headerBytes, err := connection.Peek(16)
+// Source is buffered input that frames are decoded from, it is implemented by gnet.Conn and Buffer.
+type Source interface {
+	InboundBuffered() int
+	Peek(n int) ([]byte, error)
+	Discard(n int) (int, error)
+}
 
-	if err != nil {
-		return nil, fmt.Errorf("error reading type byte: %v", err)
-	}
+// FromConnection reads the next frame of the connection, see Decode.
+func FromConnection(connection gnet.Conn) (proto.Message, error) {
+	return Decode(connection)
+}
 
-	messageType := int(binary.BigEndian.Uint64(headerBytes[0:8]))
-	messageLength := int(binary.BigEndian.Uint64(headerBytes[8:16]))
+// Decode reads the next frame of the source with the DefaultRegistry, see Registry.Decode.
+func Decode(source Source) (proto.Message, error) {
+	return DefaultRegistry.Decode(source)
+}
 
-	var message proto.Message
+// Decode reads the next frame of the source. It returns ErrIncompleteFrame and consumes nothing until the
+// whole frame is buffered. Corrupt input is skipped up to the next magic, frames of unknown versions or types are
+// skipped as a whole, so decoding can continue after any of the returned errors. Messages in an Envelope are unwrapped.
+func (r *Registry) Decode(source Source) (proto.Message, error) {
+	buffered := source.InboundBuffered()
 
-	switch messageType {
-	case AudioType:
-		message = &Audio{}
-	case TimeType:
-		message = &Time{}
-	case LatencyType:
-		message = &Latency{}
-	default:
-		return nil, fmt.Errorf("unsupported message type: %v", messageType)
+	if buffered < HeaderSize {
+		return nil, ErrIncompleteFrame
 	}
 
-	_, err = connection.Discard(16)
+	headerBytes, err := source.Peek(HeaderSize)
 
 	if err != nil {
-		return nil, errors.Wrap(err, "error discarding header bytes")
+		return nil, fmt.Errorf("error reading header: %v", err)
 	}
 
-	peekedBytes, err := connection.Peek(messageLength)
+	h, err := parseHeader(headerBytes)
 
 	if err != nil {
-		return nil, fmt.Errorf("error reading message: %v", err)
+		return nil, resync(source, err)
 	}
 
-	if len(peekedBytes) != messageLength {
-		return nil, fmt.Errorf("error reading message: expected %v bytes, got %v", messageLength, len(peekedBytes))
+	if buffered < HeaderSize+h.length {
+		return nil, ErrIncompleteFrame
 	}
 
-	messageBytes := make([]byte, messageLength)
-
-	bytesRead, err := connection.Read(messageBytes)
+	frame, err := source.Peek(HeaderSize + h.length)
 
 	if err != nil {
 		return nil, fmt.Errorf("error reading message: %v", err)
 	}
 
-	if bytesRead != messageLength {
-		return nil, fmt.Errorf("error reading message: expected %v bytes, got %v", messageLength, bytesRead)
+	if crc32.ChecksumIEEE(frame[HeaderSize:]) != h.checksum {
+		return nil, resync(source, errors.Wrap(ErrCorruptFrame, "message checksum mismatch"))
 	}
 
-	err = proto.Unmarshal(messageBytes, message)
+	// the frame is intact from here on, so it is skipped as a whole if it cannot be read
+	defer source.Discard(HeaderSize + h.length)
 
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling message: %v", err)
+	if h.version != Version {
+		return nil, errors.Wrapf(ErrUnsupportedVersion, "version %d", h.version)
 	}
 
-	return message, nil
-}
-
-func (p *Packet) Message() proto.Message {
-	return p.message
-}
-
-func (p *Packet) HeaderBytes() ([]byte, error) {
-	mts := p.MessageTypeBytes()
-	mlb, err := p.MessageLengthBytes()
-
-	if err != nil {
-		return nil, err
+	if h.flags != 0 {
+		return nil, errors.Wrapf(ErrUnsupportedFlags, "flags %#x", h.flags)
 	}
 
-	b := make([]byte, 16)
-	copy(b[0:8], mts)
-	copy(b[8:16], mlb)
-
-	return b, nil
-}
-
-func (p *Packet) MessageTypeBytes() []byte {
-	b := make([]byte, 8)
-	binary.BigEndian.PutUint64(b, uint64(p.mtype))
-
-	return b
-}
-
-func (p *Packet) MessageBytes() ([]byte, error) {
-	mb, err := ToBytes(p.message)
+	message, err := r.New(h.mtype)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return mb, nil
-}
-
-func (p *Packet) Bytes() ([]byte, error) {
-	hb, err := p.HeaderBytes()
+	err = proto.Unmarshal(frame[HeaderSize:], message)
 
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("error unmarshalling message: %v", err)
 	}
 
-	mb, err := p.MessageBytes()
-
-	if err != nil {
-		return nil, err
+	if envelope, ok := message.(*Envelope); ok {
+		return envelope.Unwrap()
 	}
 
-	return append(hb, mb...), nil
+	return message, nil
 }
 
-func (p *Packet) MessageLengthBytes() ([]byte, error) {
-	mb, err := p.MessageBytes()
+// resync discards the buffered input up to the next magic.
+func resync(source Source, cause error) error {
+	buffered, err := source.Peek(source.InboundBuffered())
 
 	if err != nil {
-		return nil, err
+		return errors.Wrap(err, "error reading input to resynchronize")
 	}
 
-	b := make([]byte, 8)
-	binary.BigEndian.PutUint64(b, uint64(len(mb)))
+	skipped, _ := source.Discard(resyncOffset(buffered))
+
+	return errors.Wrapf(cause, "skipped %d bytes", skipped)
+}
 
-	return b, nil
+func (p *Packet) Message() proto.Message {
+	return p.message
+}
+
+// Bytes returns the frame of the packet, it fails with ErrUnsupportedType for messages the Envelope cannot carry.
+func (p *Packet) Bytes() ([]byte, error) {
+	return DefaultRegistry.Marshal(p.message)
 }
@@ -0,0 +1,255 @@
+package messages
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func testMessages() []proto.Message {
+	return []proto.Message{
+		&Time{Time: timestamppb.New(time.Unix(1, 0))},
+		NewAudio(ChannelLayout_CHANNEL_LAYOUT_STEREO, []float64{0.1, 0.2, 0.3}, []float64{-0.1, -0.2, -0.3}),
+		&Latency{Latency: 42, Time: timestamppb.New(time.Unix(7, 0))},
+		&NowPlaying{TrackId: "track", Title: "Title", Artwork: make([]byte, 10000)},
+		&Subscription{Topics: []Topic{Topic_TOPIC_PLAYBACK_STATE}},
+	}
+}
+
+func testStream(t testing.TB, messages []proto.Message) []byte {
+	var stream []byte
+
+	for _, message := range messages {
+		frame, err := ToPacket(message).Bytes()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		stream = append(stream, frame...)
+	}
+
+	return stream
+}
+
+// sealHeader updates the header checksum of a frame after its header was changed.
+func sealHeader(frame []byte) []byte {
+	binary.BigEndian.PutUint32(frame[12:16], crc32.ChecksumIEEE(frame[0:12]))
+
+	return frame
+}
+
+// decodeAll decodes all complete frames of the buffer and returns the messages and the amount of errors.
+func decodeAll(t testing.TB, buffer *Buffer) ([]proto.Message, int) {
+	var messages []proto.Message
+	failures := 0
+
+	for {
+		buffered := buffer.InboundBuffered()
+		message, err := buffer.Next()
+
+		if errors.Is(err, ErrIncompleteFrame) {
+			return messages, failures
+		}
+
+		if err != nil {
+			if buffer.InboundBuffered() == buffered {
+				t.Fatalf("decoding made no progress after %v", err)
+			}
+
+			failures++
+
+			continue
+		}
+
+		messages = append(messages, message)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Run(
+		"should decode frames split into arbitrary pieces",
+		func(t *testing.T) {
+			expected := testMessages()
+			stream := testStream(t, expected)
+			random := rand.New(rand.NewSource(1))
+
+			for _, maxPiece := range []int{1, 3, 16, 17, 100, 5000, len(stream)} {
+				buffer := &Buffer{}
+				var decoded []proto.Message
+
+				for offset := 0; offset < len(stream); {
+					end := offset + 1 + random.Intn(maxPiece)
+
+					if end > len(stream) {
+						end = len(stream)
+					}
+
+					_, _ = buffer.Write(stream[offset:end])
+					offset = end
+
+					messages, failures := decodeAll(t, buffer)
+
+					if failures > 0 {
+						t.Fatalf("%d errors decoding pieces of up to %d bytes", failures, maxPiece)
+					}
+
+					decoded = append(decoded, messages...)
+				}
+
+				if len(decoded) != len(expected) {
+					t.Fatalf("decoded %d of %d messages in pieces of up to %d bytes", len(decoded), len(expected), maxPiece)
+				}
+
+				for i := range expected {
+					if !proto.Equal(decoded[i], expected[i]) {
+						t.Fatalf("message %d differs: %v", i, decoded[i])
+					}
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"should resynchronize after corrupt input",
+		func(t *testing.T) {
+			expected := testMessages()
+			first := testStream(t, expected[:2])
+			second := testStream(t, expected[2:3])
+			third := testStream(t, expected[3:])
+
+			// flip a bit in the message of the second frame
+			second[HeaderSize+1] ^= 0x01
+
+			buffer := &Buffer{}
+			_, _ = buffer.Write([]byte("garbage before the first frame NA"))
+			_, _ = buffer.Write(first)
+			_, _ = buffer.Write([]byte{0x00, 'N', 'A', 'U'})
+			_, _ = buffer.Write(second)
+			_, _ = buffer.Write(third)
+
+			decoded, failures := decodeAll(t, buffer)
+
+			if failures == 0 {
+				t.Fatal("corrupt input was not reported")
+			}
+
+			if len(decoded) != 4 || !proto.Equal(decoded[0], expected[0]) || !proto.Equal(decoded[2], expected[3]) {
+				t.Fatalf("decoded %v", decoded)
+			}
+		},
+	)
+
+	t.Run(
+		"should reject frames larger than the maximum without waiting for them",
+		func(t *testing.T) {
+			frame := testStream(t, []proto.Message{&Time{Time: timestamppb.New(time.Unix(1, 0))}})
+			binary.BigEndian.PutUint32(frame[8:12], MaxFrameSize+1)
+
+			buffer := &Buffer{}
+			_, _ = buffer.Write(sealHeader(frame))
+
+			_, err := buffer.Next()
+
+			if !errors.Is(err, ErrFrameTooLarge) {
+				t.Fatalf("expected frame too large, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"should detect a corrupt length without waiting for the frame",
+		func(t *testing.T) {
+			expected := testMessages()
+			stream := testStream(t, expected)
+
+			// the length claims almost the maximum, only the header and the following frames are buffered
+			binary.BigEndian.PutUint32(stream[8:12], MaxFrameSize-1)
+
+			buffer := &Buffer{}
+			_, _ = buffer.Write(stream[:HeaderSize])
+
+			if _, err := buffer.Next(); !errors.Is(err, ErrCorruptFrame) {
+				t.Fatalf("expected a corrupt frame, got %v", err)
+			}
+
+			_, _ = buffer.Write(stream[HeaderSize:])
+
+			decoded, _ := decodeAll(t, buffer)
+
+			if len(decoded) != len(expected)-1 || !proto.Equal(decoded[0], expected[1]) {
+				t.Fatalf("decoded %v", decoded)
+			}
+		},
+	)
+
+	t.Run(
+		"should skip frames of unknown types, versions and flags",
+		func(t *testing.T) {
+			unknownType, _ := encodeFrame(0x7fff, []byte{1})
+			unknownVersion, _ := encodeFrame(EnvelopeType, []byte{})
+			unknownVersion[4] = Version + 1
+			unknownFlags, _ := encodeFrame(EnvelopeType, []byte{})
+			unknownFlags[5] = 0x01
+
+			buffer := &Buffer{}
+			_, _ = buffer.Write(unknownType)
+			_, _ = buffer.Write(sealHeader(unknownVersion))
+			_, _ = buffer.Write(sealHeader(unknownFlags))
+			_, _ = buffer.Write(testStream(t, []proto.Message{&Time{Time: timestamppb.New(time.Unix(1, 0))}}))
+
+			if _, err := buffer.Next(); !errors.Is(err, ErrUnsupportedType) {
+				t.Fatalf("expected unsupported type, got %v", err)
+			}
+
+			if _, err := buffer.Next(); !errors.Is(err, ErrUnsupportedVersion) {
+				t.Fatalf("expected unsupported version, got %v", err)
+			}
+
+			if _, err := buffer.Next(); !errors.Is(err, ErrUnsupportedFlags) {
+				t.Fatalf("expected unsupported flags, got %v", err)
+			}
+
+			if message, err := buffer.Next(); err != nil || message.(*Time).Time.Seconds != 1 {
+				t.Fatalf("expected the time message, got %v, %v", message, err)
+			}
+		},
+	)
+}
+
+func FuzzDecode(f *testing.F) {
+	stream := testStream(f, testMessages())
+
+	f.Add(stream)
+	f.Add(stream[:HeaderSize+3])
+	f.Add(append([]byte("NAUDNAUD"), stream...))
+
+	f.Fuzz(
+		func(t *testing.T, input []byte) {
+			buffer := &Buffer{}
+
+			// feed the input in pieces to exercise partial frames, decodeAll fails if decoding gets stuck
+			for _, piece := range [][]byte{input[:len(input)/2], input[len(input)/2:], stream} {
+				_, _ = buffer.Write(piece)
+
+				messages, _ := decodeAll(t, buffer)
+
+				for _, message := range messages {
+					if _, err := ToPacket(message).Bytes(); err != nil {
+						t.Fatalf("decoded message cannot be encoded: %v", err)
+					}
+				}
+			}
+
+			if buffer.InboundBuffered() > HeaderSize+MaxFrameSize {
+				t.Fatalf("buffer holds %d bytes", buffer.InboundBuffered())
+			}
+		},
+	)
+}
@@ -0,0 +1,253 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.0
+// 	protoc        v3.19.4
+// source: playback_state.proto
+
+package messages
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PlaybackStatus int32
+
+const (
+	PlaybackStatus_PLAYBACK_STATUS_STOPPED PlaybackStatus = 0
+	PlaybackStatus_PLAYBACK_STATUS_PLAYING PlaybackStatus = 1
+)
+
+// Enum value maps for PlaybackStatus.
+var (
+	PlaybackStatus_name = map[int32]string{
+		0: "PLAYBACK_STATUS_STOPPED",
+		1: "PLAYBACK_STATUS_PLAYING",
+	}
+	PlaybackStatus_value = map[string]int32{
+		"PLAYBACK_STATUS_STOPPED": 0,
+		"PLAYBACK_STATUS_PLAYING": 1,
+	}
+)
+
+func (x PlaybackStatus) Enum() *PlaybackStatus {
+	p := new(PlaybackStatus)
+	*p = x
+	return p
+}
+
+func (x PlaybackStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PlaybackStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_playback_state_proto_enumTypes[0].Descriptor()
+}
+
+func (PlaybackStatus) Type() protoreflect.EnumType {
+	return &file_playback_state_proto_enumTypes[0]
+}
+
+func (x PlaybackStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PlaybackStatus.Descriptor instead.
+func (PlaybackStatus) EnumDescriptor() ([]byte, []int) {
+	return file_playback_state_proto_rawDescGZIP(), []int{0}
+}
+
+type PlaybackState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State    PlaybackStatus         `protobuf:"varint,1,opt,name=state,proto3,enum=message.PlaybackStatus" json:"state,omitempty"`
+	TrackId  string                 `protobuf:"bytes,2,opt,name=track_id,json=trackId,proto3" json:"track_id,omitempty"`
+	Position int64                  `protobuf:"varint,3,opt,name=position,proto3" json:"position,omitempty"`
+	Duration int64                  `protobuf:"varint,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	Time     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=time,proto3" json:"time,omitempty"`
+	Frames   uint64                 `protobuf:"varint,6,opt,name=frames,proto3" json:"frames,omitempty"`
+}
+
+func (x *PlaybackState) Reset() {
+	*x = PlaybackState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_playback_state_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PlaybackState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaybackState) ProtoMessage() {}
+
+func (x *PlaybackState) ProtoReflect() protoreflect.Message {
+	mi := &file_playback_state_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaybackState.ProtoReflect.Descriptor instead.
+func (*PlaybackState) Descriptor() ([]byte, []int) {
+	return file_playback_state_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PlaybackState) GetState() PlaybackStatus {
+	if x != nil {
+		return x.State
+	}
+	return PlaybackStatus_PLAYBACK_STATUS_STOPPED
+}
+
+func (x *PlaybackState) GetTrackId() string {
+	if x != nil {
+		return x.TrackId
+	}
+	return ""
+}
+
+func (x *PlaybackState) GetPosition() int64 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *PlaybackState) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *PlaybackState) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *PlaybackState) GetFrames() uint64 {
+	if x != nil {
+		return x.Frames
+	}
+	return 0
+}
+
+var File_playback_state_proto protoreflect.FileDescriptor
+
+var file_playback_state_proto_rawDesc = []byte{
+	0x0a, 0x14, 0x70, 0x6c, 0x61, 0x79, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a,
+	0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
+	0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x22, 0xd9, 0x01, 0x0a, 0x0d, 0x50, 0x6c, 0x61, 0x79, 0x62, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x12, 0x2d, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x17, 0x2e, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x50, 0x6c, 0x61, 0x79,
+	0x62, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08,
+	0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08,
+	0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2e, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04,
+	0x74, 0x69, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x66, 0x72, 0x61, 0x6d, 0x65, 0x73, 0x2a, 0x4a, 0x0a, 0x0e,
+	0x50, 0x6c, 0x61, 0x79, 0x62, 0x61, 0x63, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1b,
+	0x0a, 0x17, 0x50, 0x4c, 0x41, 0x59, 0x42, 0x41, 0x43, 0x4b, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55,
+	0x53, 0x5f, 0x53, 0x54, 0x4f, 0x50, 0x50, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1b, 0x0a, 0x17, 0x50,
+	0x4c, 0x41, 0x59, 0x42, 0x41, 0x43, 0x4b, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x50,
+	0x4c, 0x41, 0x59, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x42, 0x0c, 0x5a, 0x0a, 0x2e, 0x2f, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_playback_state_proto_rawDescOnce sync.Once
+	file_playback_state_proto_rawDescData = file_playback_state_proto_rawDesc
+)
+
+func file_playback_state_proto_rawDescGZIP() []byte {
+	file_playback_state_proto_rawDescOnce.Do(func() {
+		file_playback_state_proto_rawDescData = protoimpl.X.CompressGZIP(file_playback_state_proto_rawDescData)
+	})
+	return file_playback_state_proto_rawDescData
+}
+
+var file_playback_state_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_playback_state_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_playback_state_proto_goTypes = []interface{}{
+	(PlaybackStatus)(0),           // 0: message.PlaybackStatus
+	(*PlaybackState)(nil),         // 1: message.PlaybackState
+	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+}
+var file_playback_state_proto_depIdxs = []int32{
+	0, // 0: message.PlaybackState.state:type_name -> message.PlaybackStatus
+	2, // 1: message.PlaybackState.time:type_name -> google.protobuf.Timestamp
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_playback_state_proto_init() }
+func file_playback_state_proto_init() {
+	if File_playback_state_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_playback_state_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PlaybackState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_playback_state_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_playback_state_proto_goTypes,
+		DependencyIndexes: file_playback_state_proto_depIdxs,
+		EnumInfos:         file_playback_state_proto_enumTypes,
+		MessageInfos:      file_playback_state_proto_msgTypes,
+	}.Build()
+	File_playback_state_proto = out.File
+	file_playback_state_proto_rawDesc = nil
+	file_playback_state_proto_goTypes = nil
+	file_playback_state_proto_depIdxs = nil
+}
@@ -0,0 +1,129 @@
+package messages
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Registry maps message types to the codes in the frame header. The protocol only needs the Envelope, see
+// EnvelopeType, other registries may carry their own messages.
+type Registry struct {
+	codes map[protoreflect.FullName]int
+	types map[int]protoreflect.MessageType
+	lock  *sync.RWMutex
+
+	// registers the initial messages on first use, the generated descriptors are not ready during package initialization
+	setup *sync.Once
+	init  func(r *Registry)
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		codes: map[protoreflect.FullName]int{},
+		types: map[int]protoreflect.MessageType{},
+		lock:  &sync.RWMutex{},
+		setup: &sync.Once{},
+	}
+}
+
+// DefaultRegistry holds the messages of the protocol, it is used by the package level functions.
+var DefaultRegistry = newDefaultRegistry()
+
+func newDefaultRegistry() *Registry {
+	r := NewRegistry()
+	r.init = registerProtocol
+
+	return r
+}
+
+func registerProtocol(r *Registry) {
+	err := r.register(EnvelopeType, &Envelope{})
+
+	if err != nil {
+		panic(err)
+	}
+}
+
+func (r *Registry) initialize() {
+	r.setup.Do(
+		func() {
+			if r.init != nil {
+				r.init(r)
+			}
+		},
+	)
+}
+
+// Register adds a message type with its code to the DefaultRegistry.
+func Register(code int, message proto.Message) error {
+	return DefaultRegistry.Register(code, message)
+}
+
+// Register adds a message type with its code, neither may be registered already.
+func (r *Registry) Register(code int, message proto.Message) error {
+	r.initialize()
+
+	return r.register(code, message)
+}
+
+func (r *Registry) register(code int, message proto.Message) error {
+	if code < 0 || code > 0xffff {
+		return errors.Errorf("message code %d out of range", code)
+	}
+
+	messageType := message.ProtoReflect().Type()
+	name := messageType.Descriptor().FullName()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if existing, ok := r.types[code]; ok {
+		return errors.Errorf("message code %d is already registered for %s", code, existing.Descriptor().FullName())
+	}
+
+	if existing, ok := r.codes[name]; ok {
+		return errors.Errorf("message %s is already registered with code %d", name, existing)
+	}
+
+	r.codes[name] = code
+	r.types[code] = messageType
+
+	return nil
+}
+
+// Code returns the code of the message type.
+func (r *Registry) Code(message proto.Message) (int, error) {
+	r.initialize()
+
+	name := message.ProtoReflect().Descriptor().FullName()
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	code, ok := r.codes[name]
+
+	if !ok {
+		return 0, errors.Wrapf(ErrUnsupportedType, "%s", name)
+	}
+
+	return code, nil
+}
+
+// New creates an empty message of the type with the code.
+func (r *Registry) New(code int) (proto.Message, error) {
+	r.initialize()
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	messageType, ok := r.types[code]
+
+	if !ok {
+		return nil, errors.Wrapf(ErrUnsupportedType, "type %d", code)
+	}
+
+	return messageType.New().Interface(), nil
+}
@@ -0,0 +1,301 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/pkg/errors"
+)
+
+// id3Names maps the ID3 text frames to the names of the Vorbis comments.
+var id3Names = map[string]string{
+	"TIT2": "TITLE",
+	"TT2":  "TITLE",
+	"TPE1": "ARTIST",
+	"TP1":  "ARTIST",
+	"TALB": "ALBUM",
+	"TAL":  "ALBUM",
+	"TRCK": "TRACKNUMBER",
+	"TRK":  "TRACKNUMBER",
+	"TCON": "GENRE",
+	"TCO":  "GENRE",
+	"TYER": "DATE",
+	"TYE":  "DATE",
+	"TDRC": "DATE",
+}
+
+// ReadID3 reads an ID3v2 tag at the start of the file and falls back to an ID3v1 tag at its end.
+func ReadID3(r io.ReadSeeker) (*Metadata, error) {
+	m := newMetadata()
+	header := make([]byte, 10)
+
+	_, err := io.ReadFull(r, header)
+
+	if err == nil && bytes.Equal(header[0:3], []byte("ID3")) {
+		err = readID3v2(r, header, m)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return m, nil
+	}
+
+	return m, readID3v1(r, m)
+}
+
+func readID3v2(r io.Reader, header []byte, m *Metadata) error {
+	version := header[3]
+	flags := header[5]
+	size := syncsafe(header[6:10])
+
+	// the size is read from the file, the tag is only allocated as far as the file holds it
+	tag, err := io.ReadAll(io.LimitReader(r, int64(size)))
+
+	if err != nil {
+		return errors.Wrap(err, "error reading id3 tag")
+	}
+
+	if len(tag) < size {
+		return errors.Wrap(io.ErrUnexpectedEOF, "error reading id3 tag")
+	}
+
+	// unsynchronisation of the whole tag, ID3v2.4 marks it per frame
+	if flags&0x80 != 0 && version < 4 {
+		tag = unsynchronise(tag)
+	}
+
+	// skip the extended header
+	if flags&0x40 != 0 && len(tag) >= 4 {
+		extendedSize := int(binary.BigEndian.Uint32(tag[0:4]))
+
+		if version >= 4 {
+			extendedSize = syncsafe(tag[0:4])
+		} else {
+			extendedSize += 4
+		}
+
+		if extendedSize > len(tag) {
+			return errors.New("invalid id3 extended header")
+		}
+
+		tag = tag[extendedSize:]
+	}
+
+	idSize, headerSize := 4, 10
+
+	if version == 2 {
+		idSize, headerSize = 3, 6
+	}
+
+	for len(tag) >= headerSize && tag[0] != 0 {
+		id := string(tag[0:idSize])
+
+		var frameSize int
+
+		switch version {
+		case 2:
+			frameSize = int(tag[3])<<16 | int(tag[4])<<8 | int(tag[5])
+		case 3:
+			frameSize = int(binary.BigEndian.Uint32(tag[4:8]))
+		default:
+			frameSize = syncsafe(tag[4:8])
+		}
+
+		if frameSize > len(tag)-headerSize {
+			break
+		}
+
+		frame := tag[headerSize : headerSize+frameSize]
+
+		if version >= 4 && tag[9]&0x02 != 0 {
+			frame = unsynchronise(frame)
+		}
+
+		tag = tag[headerSize+frameSize:]
+
+		readID3Frame(id, frame, m)
+	}
+
+	return nil
+}
+
+func readID3Frame(id string, frame []byte, m *Metadata) {
+	if len(frame) == 0 {
+		return
+	}
+
+	switch {
+	case id == "TXXX" || id == "TXX":
+		parts := splitEncoded(frame[0], frame[1:], 2)
+
+		if len(parts) == 2 {
+			m.set(parts[0], parts[1])
+		}
+	case id == "APIC":
+		readAPIC(frame, m)
+	case id == "PIC":
+		readPIC(frame, m)
+	case strings.HasPrefix(id, "T"):
+		name, ok := id3Names[id]
+
+		if !ok {
+			name = id
+		}
+
+		// multiple values are separated with a null character
+		m.set(name, strings.Join(splitEncoded(frame[0], frame[1:], -1), "/"))
+	}
+}
+
+func readAPIC(frame []byte, m *Metadata) {
+	encoding := frame[0]
+	mimeEnd := bytes.IndexByte(frame[1:], 0)
+
+	if mimeEnd < 0 || 1+mimeEnd+2 > len(frame) {
+		return
+	}
+
+	mime := string(frame[1 : 1+mimeEnd])
+	rest := frame[1+mimeEnd+2:]
+	_, data := cutEncoded(encoding, rest)
+
+	setArtwork(m, mime, data)
+}
+
+func readPIC(frame []byte, m *Metadata) {
+	if len(frame) < 5 {
+		return
+	}
+
+	mime := "image/" + strings.ToLower(string(frame[1:4]))
+	_, data := cutEncoded(frame[0], frame[5:])
+
+	setArtwork(m, mime, data)
+}
+
+func setArtwork(m *Metadata, mime string, data []byte) {
+	// keep the first picture, usually the front cover
+	if m.Artwork != nil || len(data) == 0 {
+		return
+	}
+
+	if mime == "image/jpg" {
+		mime = "image/jpeg"
+	}
+
+	m.Artwork = data
+	m.ArtworkType = mime
+}
+
+// splitEncoded decodes up to n null terminated strings, n < 0 decodes all.
+func splitEncoded(encoding byte, data []byte, n int) []string {
+	parts := []string{}
+
+	for len(data) > 0 && (n < 0 || len(parts) < n-1) {
+		var part []byte
+
+		part, data = cutEncoded(encoding, data)
+		parts = append(parts, decodeText(encoding, part))
+	}
+
+	if len(data) > 0 {
+		parts = append(parts, decodeText(encoding, data))
+	}
+
+	return parts
+}
+
+// cutEncoded splits the data at the first null terminator of the encoding.
+func cutEncoded(encoding byte, data []byte) ([]byte, []byte) {
+	if encoding == 1 || encoding == 2 {
+		for i := 0; i+1 < len(data); i += 2 {
+			if data[i] == 0 && data[i+1] == 0 {
+				return data[:i], data[i+2:]
+			}
+		}
+
+		return data, nil
+	}
+
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return data[:i], data[i+1:]
+	}
+
+	return data, nil
+}
+
+func decodeText(encoding byte, data []byte) string {
+	switch encoding {
+	case 1, 2:
+		bigEndian := encoding == 2
+
+		if len(data) >= 2 && data[0] == 0xFE && data[1] == 0xFF {
+			bigEndian, data = true, data[2:]
+		} else if len(data) >= 2 && data[0] == 0xFF && data[1] == 0xFE {
+			bigEndian, data = false, data[2:]
+		}
+
+		units := make([]uint16, len(data)/2)
+
+		for i := range units {
+			if bigEndian {
+				units[i] = binary.BigEndian.Uint16(data[i*2:])
+			} else {
+				units[i] = binary.LittleEndian.Uint16(data[i*2:])
+			}
+		}
+
+		return string(utf16.Decode(units))
+	case 3:
+		return string(data)
+	default:
+		runes := make([]rune, len(data))
+
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+
+		return string(runes)
+	}
+}
+
+func readID3v1(r io.ReadSeeker, m *Metadata) error {
+	if _, err := r.Seek(-128, io.SeekEnd); err != nil {
+		// files shorter than an ID3v1 tag have no tags
+		return nil
+	}
+
+	tag := make([]byte, 128)
+
+	if _, err := io.ReadFull(r, tag); err != nil {
+		return errors.Wrap(err, "error reading id3v1 tag")
+	}
+
+	if !bytes.Equal(tag[0:3], []byte("TAG")) {
+		return nil
+	}
+
+	field := func(data []byte) string {
+		return strings.TrimSpace(decodeText(0, bytes.TrimRight(data, "\x00")))
+	}
+
+	m.set("TITLE", field(tag[3:33]))
+	m.set("ARTIST", field(tag[33:63]))
+	m.set("ALBUM", field(tag[63:93]))
+	m.set("DATE", field(tag[93:97]))
+
+	return nil
+}
+
+func syncsafe(b []byte) int {
+	return int(b[0]&0x7F)<<21 | int(b[1]&0x7F)<<14 | int(b[2]&0x7F)<<7 | int(b[3]&0x7F)
+}
+
+// unsynchronise removes the 0x00 inserted after every 0xFF.
+func unsynchronise(data []byte) []byte {
+	return bytes.ReplaceAll(data, []byte{0xFF, 0x00}, []byte{0xFF})
+}
@@ -0,0 +1,304 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"unicode/utf16"
+)
+
+// id3Frame encodes a frame of the ID3v2 version with the flags, ID3v2.2 frames have no flags.
+func id3Frame(version byte, id string, flags byte, data []byte) []byte {
+	frame := []byte(id)
+
+	switch version {
+	case 2:
+		frame = append(frame, byte(len(data)>>16), byte(len(data)>>8), byte(len(data)))
+	case 3:
+		frame = append(frame, uint32Bytes(len(data))...)
+		frame = append(frame, 0, flags)
+	default:
+		frame = append(frame, syncsafeBytes(len(data))...)
+		frame = append(frame, 0, flags)
+	}
+
+	return append(frame, data...)
+}
+
+// id3Tag encodes an ID3v2 tag of the frames.
+func id3Tag(version byte, flags byte, frames ...[]byte) []byte {
+	body := bytes.Join(frames, nil)
+	tag := append([]byte{'I', 'D', '3', version, 0, flags}, syncsafeBytes(len(body))...)
+
+	return append(tag, body...)
+}
+
+func uint32Bytes(size int) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, uint32(size))
+
+	return b
+}
+
+func syncsafeBytes(size int) []byte {
+	return []byte{byte(size>>21) & 0x7F, byte(size>>14) & 0x7F, byte(size>>7) & 0x7F, byte(size) & 0x7F}
+}
+
+func utf16LE(s string, bom bool) []byte {
+	var data []byte
+
+	if bom {
+		data = append(data, 0xFF, 0xFE)
+	}
+
+	for _, unit := range utf16.Encode([]rune(s)) {
+		data = append(data, byte(unit), byte(unit>>8))
+	}
+
+	return data
+}
+
+func utf16BE(s string) []byte {
+	var data []byte
+
+	for _, unit := range utf16.Encode([]rune(s)) {
+		data = append(data, byte(unit>>8), byte(unit))
+	}
+
+	return data
+}
+
+func id3v1Tag(title, artist, album, year string) []byte {
+	tag := make([]byte, 128)
+
+	copy(tag, "TAG")
+	copy(tag[3:33], title)
+	copy(tag[33:63], artist)
+	copy(tag[63:93], album)
+	copy(tag[93:97], year)
+
+	return tag
+}
+
+// synchronise inserts a 0x00 after every 0xFF of the tag body and updates the size of the tag.
+func synchronise(tag []byte) []byte {
+	body := bytes.ReplaceAll(tag[10:], []byte{0xFF}, []byte{0xFF, 0x00})
+
+	return join(tag[:6], syncsafeBytes(len(body)), body)
+}
+
+func join(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func TestReadID3(t *testing.T) {
+	picture := []byte{0xFF, 0xD8, 0xFF, 0x00, 0x01}
+
+	tests := []struct {
+		name     string
+		file     []byte
+		expected map[string]string
+		artwork  []byte
+		mime     string
+	}{
+		{
+			name: "should read ID3v2.2 frames",
+			file: id3Tag(
+				2, 0,
+				id3Frame(2, "TT2", 0, []byte("\x00Title")),
+				id3Frame(2, "TP1", 0, []byte("\x00Artist")),
+				id3Frame(2, "TXX", 0, []byte("\x00REPLAYGAIN_TRACK_GAIN\x00-6.5 dB")),
+				id3Frame(2, "PIC", 0, join([]byte("\x00JPG\x03Cover\x00"), picture)),
+			),
+			expected: map[string]string{"TITLE": "Title", "ARTIST": "Artist", "REPLAYGAIN_TRACK_GAIN": "-6.5 dB"},
+			artwork:  picture,
+			mime:     "image/jpeg",
+		},
+		{
+			name: "should read ID3v2.3 frames in UTF-16 with byte order mark",
+			file: id3Tag(
+				3, 0,
+				id3Frame(3, "TIT2", 0, join([]byte{1}, utf16LE("Tïtle ♫", true))),
+				id3Frame(3, "TALB", 0, join([]byte{1}, utf16LE("Album", true), []byte{0, 0})),
+				id3Frame(
+					3, "TXXX", 0,
+					join([]byte{1}, utf16LE("REPLAYGAIN_ALBUM_GAIN", true), []byte{0, 0}, utf16LE("-3 dB", true)),
+				),
+				id3Frame(3, "APIC", 0, join([]byte("\x00image/png\x00\x03Cover\x00"), picture)),
+			),
+			expected: map[string]string{"TITLE": "Tïtle ♫", "ALBUM": "Album", "REPLAYGAIN_ALBUM_GAIN": "-3 dB"},
+			artwork:  picture,
+			mime:     "image/png",
+		},
+		{
+			name: "should read ID3v2.3 tags with unsynchronisation",
+			// the whole tag is stored with a 0x00 after every 0xFF, the frame sizes are the ones before
+			file: synchronise(
+				id3Tag(
+					3, 0x80,
+					id3Frame(3, "TIT2", 0, []byte("\x00Title")),
+					id3Frame(3, "APIC", 0, join([]byte("\x00image/jpg\x00\x03\x00"), picture)),
+				),
+			),
+			expected: map[string]string{"TITLE": "Title"},
+			artwork:  picture,
+			mime:     "image/jpeg",
+		},
+		{
+			name: "should read ID3v2.4 frames in UTF-8 and UTF-16BE",
+			file: id3Tag(
+				4, 0,
+				id3Frame(4, "TIT2", 0, []byte("\x03Tïtle")),
+				id3Frame(4, "TPE1", 0, []byte("\x03First\x00Second")),
+				id3Frame(4, "TALB", 0, join([]byte{2}, utf16BE("Älbum"))),
+				// a size above 127 is encoded with syncsafe integers
+				id3Frame(4, "TCON", 0, join([]byte{3}, bytes.Repeat([]byte("a"), 300))),
+			),
+			expected: map[string]string{
+				"TITLE":  "Tïtle",
+				"ARTIST": "First/Second",
+				"ALBUM":  "Älbum",
+				"GENRE":  string(bytes.Repeat([]byte("a"), 300)),
+			},
+		},
+		{
+			name: "should read ID3v2.4 frames with unsynchronisation",
+			file: id3Tag(
+				4, 0,
+				// the picture 0xFF 0xD8 0xFF 0x00 0x01 is stored with a 0x00 after every 0xFF
+				id3Frame(4, "APIC", 0x02, []byte("\x00image/jpeg\x00\x03\x00\xFF\x00\xD8\xFF\x00\x00\x01")),
+				id3Frame(4, "TIT2", 0, []byte("\x00Title")),
+			),
+			expected: map[string]string{"TITLE": "Title"},
+			artwork:  picture,
+			mime:     "image/jpeg",
+		},
+		{
+			name: "should skip the extended header",
+			file: id3Tag(
+				4, 0x40,
+				[]byte{0, 0, 0, 6, 1, 0},
+				id3Frame(4, "TIT2", 0, []byte("\x00Title")),
+			),
+			expected: map[string]string{"TITLE": "Title"},
+		},
+		{
+			name: "should keep the first picture",
+			file: id3Tag(
+				3, 0,
+				id3Frame(3, "APIC", 0, join([]byte("\x00image/png\x00\x03\x00"), picture)),
+				id3Frame(3, "APIC", 0, []byte("\x00image/png\x00\x04\x00other")),
+			),
+			expected: map[string]string{},
+			artwork:  picture,
+			mime:     "image/png",
+		},
+		{
+			name:     "should read ID3v1 tags",
+			file:     join([]byte("audio data"), id3v1Tag("Title", "Artist", "Album", "1999")),
+			expected: map[string]string{"TITLE": "Title", "ARTIST": "Artist", "ALBUM": "Album", "DATE": "1999"},
+		},
+		{
+			name:     "should read files without tags",
+			file:     []byte("audio data"),
+			expected: map[string]string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				m, err := ReadID3(bytes.NewReader(test.file))
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(m.Tags) != len(test.expected) {
+					t.Errorf("expected the tags %v, got %v", test.expected, m.Tags)
+				}
+
+				for name, value := range test.expected {
+					if m.Tags[name] != value {
+						t.Errorf("expected %s to be %q, got %q", name, value, m.Tags[name])
+					}
+				}
+
+				if !bytes.Equal(m.Artwork, test.artwork) || m.ArtworkType != test.mime {
+					t.Errorf("expected the artwork %x of %q, got %x of %q", test.artwork, test.mime, m.Artwork, m.ArtworkType)
+				}
+			},
+		)
+	}
+}
+
+func TestReadID3Invalid(t *testing.T) {
+	t.Run(
+		"should fail on a tag larger than the file",
+		func(t *testing.T) {
+			file := []byte{'I', 'D', '3', 3, 0, 0, 0x7F, 0x7F, 0x7F, 0x7F, 'T', 'I', 'T', '2'}
+
+			if _, err := ReadID3(bytes.NewReader(file)); err == nil {
+				t.Error("expected an error")
+			}
+		},
+	)
+
+	tests := []struct {
+		name string
+		file []byte
+	}{
+		{
+			name: "should stop at a frame larger than the tag",
+			file: id3Tag(
+				3, 0,
+				id3Frame(3, "TIT2", 0, []byte("\x00Title")),
+				[]byte{'T', 'A', 'L', 'B', 0x7F, 0xFF, 0xFF, 0xFF, 0, 0, 0, 'A'},
+			),
+		},
+		{
+			name: "should stop at a truncated frame header",
+			file: id3Tag(4, 0, id3Frame(4, "TIT2", 0, []byte("\x00Title")), []byte("TAL")),
+		},
+		{
+			name: "should ignore empty and truncated frames",
+			file: id3Tag(
+				3, 0,
+				id3Frame(3, "TIT2", 0, []byte("\x00Title")),
+				id3Frame(3, "TALB", 0, nil),
+				id3Frame(3, "TXXX", 0, []byte{1, 0xFF}),
+				id3Frame(3, "APIC", 0, []byte("\x00image/png")),
+				id3Frame(3, "APIC", 0, []byte("\x00image/png\x00")),
+				id3Frame(2, "PIC", 0, []byte("\x00JP")),
+			),
+		},
+		{
+			name: "should ignore an extended header larger than the tag",
+			file: id3Tag(3, 0x40, []byte{0x7F, 0xFF, 0xFF, 0xFF}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				// errors are fine, panics are not
+				_, _ = ReadID3(bytes.NewReader(test.file))
+			},
+		)
+	}
+}
+
+func FuzzReadID3(f *testing.F) {
+	f.Add(id3Tag(2, 0, id3Frame(2, "TT2", 0, []byte("\x01\xFF\xFET\x00")), id3Frame(2, "PIC", 0, []byte("\x00PNG\x03\x00x"))))
+	f.Add(id3Tag(3, 0xC0, []byte{0, 0, 0, 6, 0, 0, 0, 0, 0, 0}, id3Frame(3, "TXXX", 0, []byte("\x00a\x00b"))))
+	f.Add(id3Tag(4, 0, id3Frame(4, "APIC", 0x02, []byte("\x02image/png\x00\x03\x00\x00\xFF\x00"))))
+	f.Add(id3v1Tag("Title", "Artist", "Album", "1999"))
+
+	f.Fuzz(
+		func(t *testing.T, file []byte) {
+			_, _ = ReadID3(bytes.NewReader(file))
+		},
+	)
+}
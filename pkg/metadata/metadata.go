@@ -0,0 +1,75 @@
+package metadata
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Metadata holds the tags of a track.
+type Metadata struct {
+	Title  string
+	Artist string
+	Album  string
+
+	Artwork     []byte
+	ArtworkType string
+
+	// All text tags by their upper case name, e.g. REPLAYGAIN_TRACK_GAIN
+	Tags map[string]string
+}
+
+func newMetadata() *Metadata {
+	return &Metadata{Tags: map[string]string{}}
+}
+
+// Read reads the tags of a file, ID3 for mp3 files and Vorbis comments for ogg files.
+// Files without tags result in empty metadata.
+func Read(path string) (*Metadata, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	var m *Metadata
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ogg", ".oga":
+		m, err = ReadVorbis(file)
+	default:
+		m, err = ReadID3(file)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if m.Title == "" {
+		m.Title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	return m, nil
+}
+
+func (m *Metadata) set(name, value string) {
+	name = strings.ToUpper(name)
+	value = strings.TrimRight(value, "\x00")
+
+	if value == "" {
+		return
+	}
+
+	m.Tags[name] = value
+
+	switch name {
+	case "TITLE":
+		m.Title = value
+	case "ARTIST":
+		m.Artist = value
+	case "ALBUM":
+		m.Album = value
+	}
+}
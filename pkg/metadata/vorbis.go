@@ -0,0 +1,85 @@
+package metadata
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"strings"
+
+	"github.com/jfreymuth/oggvorbis"
+	"github.com/pkg/errors"
+)
+
+// ReadVorbis reads the Vorbis comments of an ogg stream.
+func ReadVorbis(r io.Reader) (*Metadata, error) {
+	header, err := oggvorbis.GetCommentHeader(r)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading vorbis comments")
+	}
+
+	m := newMetadata()
+
+	for _, comment := range header.Comments {
+		name, value, found := strings.Cut(comment, "=")
+
+		if !found {
+			continue
+		}
+
+		if strings.EqualFold(name, "METADATA_BLOCK_PICTURE") {
+			readPicture(value, m)
+			continue
+		}
+
+		m.set(name, value)
+	}
+
+	return m, nil
+}
+
+// readPicture decodes a base64 encoded FLAC picture block.
+func readPicture(value string, m *Metadata) {
+	block, err := base64.StdEncoding.DecodeString(value)
+
+	if err != nil {
+		return
+	}
+
+	field := func() []byte {
+		if len(block) < 4 {
+			return nil
+		}
+
+		size := int(binary.BigEndian.Uint32(block[0:4]))
+		block = block[4:]
+
+		if size > len(block) {
+			size = len(block)
+		}
+
+		data := block[:size]
+		block = block[size:]
+
+		return data
+	}
+
+	// picture type
+	if len(block) < 4 {
+		return
+	}
+
+	block = block[4:]
+
+	mime := string(field())
+	_ = field()
+
+	// width, height, depth and colors
+	if len(block) < 16 {
+		return
+	}
+
+	block = block[16:]
+
+	setArtwork(m, mime, field())
+}
@@ -0,0 +1,76 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+)
+
+// pictureBlock encodes a FLAC picture block of a front cover.
+func pictureBlock(mime string, data []byte) []byte {
+	return join(
+		uint32Bytes(3),
+		uint32Bytes(len(mime)), []byte(mime),
+		uint32Bytes(5), []byte("Cover"),
+		make([]byte, 16),
+		uint32Bytes(len(data)), data,
+	)
+}
+
+func TestReadPicture(t *testing.T) {
+	picture := []byte{0x89, 'P', 'N', 'G', 0x00}
+	block := pictureBlock("image/png", picture)
+
+	t.Run(
+		"should read the picture of the block",
+		func(t *testing.T) {
+			m := newMetadata()
+			readPicture(base64.StdEncoding.EncodeToString(block), m)
+
+			if !bytes.Equal(m.Artwork, picture) || m.ArtworkType != "image/png" {
+				t.Errorf("expected the artwork %x of image/png, got %x of %q", picture, m.Artwork, m.ArtworkType)
+			}
+		},
+	)
+
+	t.Run(
+		"should ignore invalid base64",
+		func(t *testing.T) {
+			m := newMetadata()
+			readPicture("not base64!", m)
+
+			if m.Artwork != nil {
+				t.Errorf("expected no artwork, got %x", m.Artwork)
+			}
+		},
+	)
+
+	t.Run(
+		"should not read beyond a truncated block",
+		func(t *testing.T) {
+			for i := 0; i < len(block); i++ {
+				m := newMetadata()
+				readPicture(base64.StdEncoding.EncodeToString(block[:i]), m)
+
+				if len(m.Artwork) > len(picture) {
+					t.Fatalf("expected at most the picture, got %x", m.Artwork)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"should clamp oversized field sizes",
+		func(t *testing.T) {
+			oversized := append([]byte{}, block...)
+			copy(oversized[len(oversized)-len(picture)-4:], uint32Bytes(0x7FFFFFFF))
+
+			m := newMetadata()
+			readPicture(base64.StdEncoding.EncodeToString(oversized), m)
+
+			if !bytes.Equal(m.Artwork, picture) {
+				t.Errorf("expected the artwork %x, got %x", picture, m.Artwork)
+			}
+		},
+	)
+}
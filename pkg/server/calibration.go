@@ -0,0 +1,174 @@
+package server
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/panjf2000/gnet/v2"
+	"github.com/pkg/errors"
+
+	"network-audio/pkg/calibration"
+	"network-audio/pkg/messages"
+	"network-audio/pkg/timex"
+)
+
+const (
+	// calibrationDelay is the time to wait after the reference client connected before the calibration starts
+	calibrationDelay = time.Second * 5
+
+	// calibrationLead is the time between the calibration request and the scheduled start of the signal
+	calibrationLead = time.Second
+
+	calibrationBlockSize = 512
+)
+
+var ErrCalibrationRunning = errors.New("calibration is already running")
+
+// WithCalibrationReference enables the automatic calibration whenever a client of the given host connects.
+// The client records the calibration signal played by all other clients.
+func WithCalibrationReference(host string) Option {
+	return func(s *Server) {
+		s.calibrationReference = host
+	}
+}
+
+// Calibrate measures the output delay of every connected client with the client of the reference host
+// and corrects their output latency offsets by the measured delay, so offsets set locally on a client are kept.
+// Every connection is measured on its own, also several clients of one host. The broadcast is muted while the
+// calibration is running.
+func (s *Server) Calibrate(reference string) error {
+	if !atomic.CompareAndSwapInt32(&s.calibrating, 0, 1) {
+		return ErrCalibrationRunning
+	}
+
+	defer atomic.StoreInt32(&s.calibrating, 0)
+
+	var recorder gnet.Conn
+	var targets []gnet.Conn
+
+	s.clients.Range(
+		func(key, value interface{}) bool {
+			connection := value.(gnet.Conn)
+
+			if recorder == nil && remoteHost(connection) == reference {
+				recorder = connection
+			} else {
+				targets = append(targets, connection)
+			}
+
+			return true
+		},
+	)
+
+	if recorder == nil {
+		return errors.Errorf("reference client %s is not connected", reference)
+	}
+
+	// the audio sent before the broadcast was muted is still playing, the signal must not overlap it
+	if wait := calibrationWait(s.player.PresentationDelay()); wait > 0 {
+		s.logger.Infof("wait %s for the broadcast to end before the calibration", wait.String())
+		time.Sleep(wait)
+	}
+
+	s.logger.Infof("start calibration of %d clients with reference %s", len(targets), reference)
+
+	for _, target := range targets {
+		name := target.RemoteAddr().String()
+		delay, err := s.calibrateTarget(recorder, target, name)
+
+		if err != nil {
+			s.logger.Warnf("calibration of %s failed: %s", name, err)
+			continue
+		}
+
+		s.logger.Infof("calibrated %s: measured delay %s", name, delay.String())
+
+		// the delay was measured with the offset of the client, it corrects that offset
+		err = s.SendTo(target, &messages.OutputLatency{Latency: delay.Nanoseconds(), Relative: true})
+
+		if err != nil {
+			s.logger.Warnf("sending output latency to %s failed: %s", name, err)
+		}
+	}
+
+	s.logger.Info("calibration done")
+
+	return nil
+}
+
+// calibrationWait returns the time the calibration waits after muting the broadcast, so the audio sent last has been
+// played before the signal starts after the calibration lead.
+func calibrationWait(presentationDelay time.Duration) time.Duration {
+	return presentationDelay - calibrationLead
+}
+
+// calibrateTarget plays the calibration signal through the target and waits for the measurement of the recorder, the
+// name identifies the target in the messages.
+func (s *Server) calibrateTarget(recorder, target gnet.Conn, name string) (time.Duration, error) {
+	rate := s.player.Format().SampleRate
+	start := time.Now().Add(calibrationLead)
+
+	// discard results of previous timed out measurements
+	select {
+	case <-s.calibrationResults:
+	default:
+	}
+
+	err := s.SendTo(
+		recorder,
+		&messages.Calibration{
+			Target:     name,
+			Start:      timex.ToTimestamp(start),
+			Duration:   calibration.RecordingDuration().Nanoseconds(),
+			SampleRate: uint32(rate),
+		},
+	)
+
+	if err != nil {
+		return 0, err
+	}
+
+	signal := calibration.Signal(rate)
+
+	for offset := 0; offset < len(signal); offset += calibrationBlockSize {
+		end := offset + calibrationBlockSize
+
+		if end > len(signal) {
+			end = len(signal)
+		}
+
+		blockTime := start.Add(rate.D(offset))
+
+		// stay ahead of the playback by half of the lead
+		time.Sleep(time.Until(blockTime.Add(-calibrationLead / 2)))
+
+		msg := messages.NewAudio(messages.ChannelLayout_CHANNEL_LAYOUT_MONO, signal[offset:end])
+		msg.Time = timex.ToTimestamp(blockTime)
+		msg.SampleRate = uint32(rate)
+
+		err = s.SendTo(target, msg)
+
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	timeout := time.After(time.Until(start.Add(calibration.RecordingDuration())) + time.Second*5)
+
+	for {
+		select {
+		case result := <-s.calibrationResults:
+			if result.Target != name {
+				continue
+			}
+
+			if result.Error != "" {
+				return 0, errors.New(result.Error)
+			}
+
+			return time.Duration(result.Delay), nil
+		case <-timeout:
+			return 0, errors.New("timeout waiting for the calibration result")
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalibrationWait(t *testing.T) {
+	t.Run(
+		"should not wait if the broadcast ends within the calibration lead",
+		func(t *testing.T) {
+			if wait := calibrationWait(time.Millisecond * 500); wait > 0 {
+				t.Errorf("expected no wait, got %s", wait)
+			}
+		},
+	)
+
+	t.Run(
+		"should wait until the signal starts after the audio sent last",
+		func(t *testing.T) {
+			delay := time.Millisecond * 2500
+
+			if wait := calibrationWait(delay); wait+calibrationLead < delay {
+				t.Errorf("expected the signal to start after %s, it starts after %s", delay, wait+calibrationLead)
+			}
+		},
+	)
+}
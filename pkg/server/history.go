@@ -0,0 +1,84 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+
+	"network-audio/pkg/messages"
+	"network-audio/pkg/timex"
+)
+
+// WithHistory keeps the audio of the duration that was sent last, new clients receive the part of it that has not
+// been played yet as burst, so they can start playing immediately. A duration of 0 disables the burst.
+func WithHistory(duration time.Duration) Option {
+	return func(s *Server) {
+		s.historyDuration = duration
+	}
+}
+
+// history holds the frames of the audio sent last, ordered by their play time.
+type history struct {
+	duration time.Duration
+	rate     beep.SampleRate
+
+	entries []historyEntry
+	lock    *sync.RWMutex
+}
+
+type historyEntry struct {
+	// the time the audio of the frame ends playing
+	end   time.Time
+	frame []byte
+}
+
+func newHistory(duration time.Duration, rate beep.SampleRate) *history {
+	return &history{
+		duration: duration,
+		rate:     rate,
+		lock:     &sync.RWMutex{},
+	}
+}
+
+// Add appends the frame of the audio message and removes the frames that end more than the duration before it.
+func (h *history) Add(m *messages.Audio, frame []byte) {
+	rate := h.rate
+
+	if m.SampleRate != 0 {
+		rate = beep.SampleRate(m.SampleRate)
+	}
+
+	end := timex.ToTime(m.Time).Add(rate.D(m.Frames()))
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.entries = append(h.entries, historyEntry{end: end, frame: frame})
+
+	expired := 0
+
+	for expired < len(h.entries) && end.Sub(h.entries[expired].end) > h.duration {
+		expired++
+	}
+
+	if expired > 0 {
+		h.entries = h.entries[:copy(h.entries, h.entries[expired:])]
+	}
+}
+
+// Pending returns the frames that end after the time, in the order they were sent.
+func (h *history) Pending(after time.Time) [][]byte {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	var frames [][]byte
+
+	for _, entry := range h.entries {
+		if entry.end.After(after) {
+			frames = append(frames, entry.frame)
+		}
+	}
+
+	return frames
+}
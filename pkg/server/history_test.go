@@ -0,0 +1,115 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"network-audio/pkg/messages"
+	"network-audio/pkg/timex"
+)
+
+func TestHistory(t *testing.T) {
+	h := newHistory(time.Second, 1000)
+	start := time.Unix(100, 0)
+
+	// blocks of 100 ms
+	for i := 0; i < 20; i++ {
+		m := messages.NewAudio(messages.ChannelLayout_CHANNEL_LAYOUT_MONO, make([]float64, 100))
+		m.Time = timex.ToTimestamp(start.Add(time.Duration(i) * time.Millisecond * 100))
+
+		h.Add(m, []byte{byte(i)})
+	}
+
+	if len(h.entries) != 11 {
+		t.Errorf("expected the blocks of the last second, got %d", len(h.entries))
+	}
+
+	pending := h.Pending(start.Add(time.Millisecond * 1750))
+
+	if len(pending) != 3 || pending[0][0] != 17 || pending[2][0] != 19 {
+		t.Errorf("expected the blocks 17 to 19, got %v", pending)
+	}
+
+	if len(h.Pending(start.Add(time.Second*2))) != 0 {
+		t.Error("expected no pending blocks after the end")
+	}
+}
+
+func TestSendHistory(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	// blocks of 100 ms that are played from the start on
+	send := func(s *Server, start time.Time, from int, to int) [][]byte {
+		var frames [][]byte
+
+		for i := from; i < to; i++ {
+			m := messages.NewAudio(messages.ChannelLayout_CHANNEL_LAYOUT_MONO, make([]float64, 4410))
+			m.SampleRate = 44100
+			m.Time = timex.ToTimestamp(start.Add(time.Duration(i) * time.Millisecond * 100))
+
+			if err := s.Send(m); err != nil {
+				t.Fatal(err)
+			}
+
+			frame, err := messages.ToPacket(m).Bytes()
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			frames = append(frames, frame)
+		}
+
+		return frames
+	}
+
+	t.Run(
+		"should send the pending history to a new connection before newer audio",
+		func(t *testing.T) {
+			s := New(logger, ":0", WithHistory(time.Second))
+			start := time.Now()
+			history := send(s, start, 0, 5)
+
+			connection := &testConnection{}
+			s.OnOpen(connection)
+
+			broadcast := send(s, start, 5, 7)
+			connection.complete()
+			connection.complete()
+
+			expected := append(history, broadcast...)
+			written := connection.written()
+
+			if len(written) != len(expected) {
+				t.Fatalf("expected %d frames, got %d", len(expected), len(written))
+			}
+
+			for i := range expected {
+				if written[i] != string(expected[i]) {
+					t.Errorf("expected the frame %d in order", i)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"should not send audio that has been played",
+		func(t *testing.T) {
+			s := New(logger, ":0", WithHistory(time.Second*10))
+			send(s, time.Now().Add(-time.Second), 0, 12)
+
+			connection := &testConnection{}
+			connection.SetContext(newOutbound(logger, connection, 256, 1024, time.Second))
+			s.sendHistory(connection)
+			connection.complete()
+
+			// the blocks 0 to 9 ended before now
+			if written := connection.written(); len(written) != 2 {
+				t.Errorf("expected the 2 pending blocks, got %d", len(written))
+			}
+		},
+	)
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"net"
+	"time"
+
+	"github.com/panjf2000/gnet/v2"
+	"google.golang.org/protobuf/proto"
+
+	"network-audio/pkg/dsp"
+	"network-audio/pkg/messages"
+)
+
+// WithOutputLatency configures the output latency offset that is sent to clients connecting from the given host.
+func WithOutputLatency(host string, latency time.Duration) Option {
+	return func(s *Server) {
+		s.outputLatencies.Store(host, latency)
+	}
+}
+
+// WithDspConfig configures the effect chain that is sent to clients connecting from the given host.
+func WithDspConfig(host string, config dsp.Config) Option {
+	return func(s *Server) {
+		s.dspConfigs.Store(host, config)
+	}
+}
+
+// SetOutputLatency stores the output latency offset for the given host and sends it to all connected clients of that host.
+func (s *Server) SetOutputLatency(host string, latency time.Duration) error {
+	s.outputLatencies.Store(host, latency)
+
+	return s.SendToHost(host, &messages.OutputLatency{Latency: latency.Nanoseconds()})
+}
+
+// GetOutputLatency returns the output latency offset configured for the given host.
+func (s *Server) GetOutputLatency(host string) time.Duration {
+	if latency, ok := s.outputLatencies.Load(host); ok {
+		return latency.(time.Duration)
+	}
+
+	return 0
+}
+
+// setOutputLatencyOf applies the output latency a client sent to the host it names or to its own host.
+func (s *Server) setOutputLatencyOf(sender string, m *messages.OutputLatency) error {
+	host := m.Host
+
+	if host == "" {
+		host = sender
+	}
+
+	latency := time.Duration(m.Latency)
+
+	if m.Relative {
+		latency += s.GetOutputLatency(host)
+	}
+
+	s.logger.Infof("%s set the output latency of %s to %s", sender, host, latency.String())
+
+	return s.SetOutputLatency(host, latency)
+}
+
+// SetDspConfig stores the effect chain for the given host and sends it to all connected clients of that host.
+func (s *Server) SetDspConfig(host string, config dsp.Config) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	s.dspConfigs.Store(host, config)
+
+	return s.SendToHost(host, dspConfigMessage(config))
+}
+
+// GetDspConfig returns the effect chain configured for the given host.
+func (s *Server) GetDspConfig(host string) (dsp.Config, bool) {
+	if config, ok := s.dspConfigs.Load(host); ok {
+		return config.(dsp.Config), true
+	}
+
+	return dsp.Config{}, false
+}
+
+// SendToHost sends the message to all connected clients of the host, including web clients.
+func (s *Server) SendToHost(host string, msg proto.Message) error {
+	var err error
+
+	if s.webSocket != nil {
+		bytes, err := messages.ToPacket(msg).Bytes()
+
+		if err != nil {
+			return err
+		}
+
+		s.webSocket.SendToHost(host, bytes, deliveryOf(msg))
+	}
+
+	s.clients.Range(
+		func(key, value interface{}) bool {
+			connection := value.(gnet.Conn)
+
+			if remoteHost(connection) != host {
+				return true
+			}
+
+			err = s.SendTo(connection, msg)
+
+			return err == nil
+		},
+	)
+
+	return err
+}
+
+// sendHostConfig sends the output latency and the dsp config of the host to a new connection.
+func (s *Server) sendHostConfig(connection gnet.Conn) error {
+	host := remoteHost(connection)
+
+	if latency, ok := s.outputLatencies.Load(host); ok {
+		err := s.SendTo(connection, &messages.OutputLatency{Latency: latency.(time.Duration).Nanoseconds()})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if config, ok := s.GetDspConfig(host); ok {
+		return s.SendTo(connection, dspConfigMessage(config))
+	}
+
+	return nil
+}
+
+func dspConfigMessage(config dsp.Config) *messages.DspConfig {
+	m := &messages.DspConfig{
+		Bass:     config.Bass,
+		Treble:   config.Treble,
+		HighPass: config.HighPass,
+		Limiter:  config.Limiter,
+	}
+
+	for _, band := range config.Bands {
+		m.Bands = append(m.Bands, &messages.EqualizerBand{Frequency: band.Frequency, Gain: band.Gain, Q: band.Q})
+	}
+
+	return m
+}
+
+func remoteHost(connection gnet.Conn) string {
+	remoteAddr := connection.RemoteAddr().String()
+	host, _, err := net.SplitHostPort(remoteAddr)
+
+	if err != nil {
+		return remoteAddr
+	}
+
+	return host
+}
@@ -0,0 +1,285 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/faiface/beep"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+
+	"network-audio/pkg/messages"
+	"network-audio/pkg/wavx"
+)
+
+const (
+	// blocks queued per listener, a listener that falls further behind loses audio
+	httpListenerBlocks = 256
+	httpMetaint        = 16000
+)
+
+// httpOutput serves the broadcast as endless WAV stream to players like browsers, VLC or smart speakers.
+// The listeners are not synchronized, they play the stream with the delay of their own buffers.
+type httpOutput struct {
+	logger logrus.FieldLogger
+	server *http.Server
+	format beep.Format
+
+	listeners *sync.Map
+	counter   uint64
+
+	// closed on Close, the streaming handlers return so the shutdown does not wait for the listeners to disconnect
+	done      chan struct{}
+	closeOnce *sync.Once
+
+	title string
+	lock  *sync.RWMutex
+}
+
+type httpListener struct {
+	blocks  chan []byte
+	dropped uint64
+}
+
+// WithHTTPOutput serves the broadcast as WAV stream on the address, e.g. ":8000".
+func WithHTTPOutput(address string) Option {
+	return func(s *Server) {
+		s.httpOutputAddress = address
+	}
+}
+
+func newHTTPOutput(logger logrus.FieldLogger, address string, format beep.Format) *httpOutput {
+	o := &httpOutput{
+		logger:    logger,
+		format:    beep.Format{SampleRate: format.SampleRate, NumChannels: 2, Precision: 2},
+		listeners: &sync.Map{},
+		done:      make(chan struct{}),
+		closeOnce: &sync.Once{},
+		lock:      &sync.RWMutex{},
+	}
+
+	o.server = &http.Server{Addr: address, Handler: o}
+
+	return o
+}
+
+func (o *httpOutput) Start() {
+	o.logger.Infof("http output is listening on %s", o.server.Addr)
+
+	go func() {
+		err := o.server.ListenAndServe()
+
+		if err != nil && err != http.ErrServerClosed {
+			o.logger.Errorf("http output error: %s\n", err)
+		}
+	}()
+}
+
+func (o *httpOutput) Close() {
+	o.closeOnce.Do(func() { close(o.done) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_ = o.server.Shutdown(ctx)
+}
+
+// Send passes the audio to the listeners and remembers the title of announced tracks.
+func (o *httpOutput) Send(msg proto.Message) {
+	switch m := msg.(type) {
+	case *messages.Audio:
+		o.write(m)
+	case *messages.NowPlaying:
+		title := m.Title
+
+		if m.Artist != "" {
+			title = m.Artist + " - " + m.Title
+		}
+
+		o.lock.Lock()
+		o.title = title
+		o.lock.Unlock()
+	}
+}
+
+func (o *httpOutput) getTitle() string {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+
+	return o.title
+}
+
+func (o *httpOutput) write(m *messages.Audio) {
+	if m.SampleRate != 0 && beep.SampleRate(m.SampleRate) != o.format.SampleRate {
+		o.logger.Warnf("dropping audio with sample rate %d for http output at %d", m.SampleRate, o.format.SampleRate)
+		return
+	}
+
+	block := wavx.Encode(o.format, stereoSamples(m))
+
+	o.listeners.Range(
+		func(key, value interface{}) bool {
+			l := value.(*httpListener)
+
+			select {
+			case l.blocks <- block:
+			default:
+				if atomic.AddUint64(&l.dropped, 1)%100 == 1 {
+					o.logger.Warnf("http listener %d is behind, dropped %d blocks", key, atomic.LoadUint64(&l.dropped))
+				}
+			}
+
+			return true
+		},
+	)
+}
+
+func (o *httpOutput) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "audio/wav")
+	w.Header().Set("Cache-Control", "no-cache, no-store")
+	w.Header().Set("icy-name", "network-audio")
+	w.Header().Set("icy-pub", "0")
+	w.Header().Set("icy-br", strconv.Itoa(int(o.format.SampleRate)*o.format.Width()*8/1000))
+
+	var writer io.Writer = w
+
+	if r.Header.Get("Icy-MetaData") == "1" {
+		w.Header().Set("icy-metaint", strconv.Itoa(httpMetaint))
+		writer = &icyWriter{writer: w, metaint: httpMetaint, remaining: httpMetaint, title: o.getTitle}
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	id := atomic.AddUint64(&o.counter, 1)
+	l := &httpListener{blocks: make(chan []byte, httpListenerBlocks)}
+
+	o.logger.Infof("http listener %d connected: %s", id, r.RemoteAddr)
+	o.listeners.Store(id, l)
+
+	defer func() {
+		o.listeners.Delete(id)
+		o.logger.Infof("http listener %d disconnected: %s", id, r.RemoteAddr)
+	}()
+
+	flusher, _ := w.(http.Flusher)
+
+	_, err := writer.Write(wavx.Header(o.format, wavx.StreamingSize))
+
+	if flusher != nil {
+		flusher.Flush()
+	}
+
+	for err == nil {
+		select {
+		case block := <-l.blocks:
+			_, err = writer.Write(block)
+
+			if flusher != nil {
+				flusher.Flush()
+			}
+		case <-r.Context().Done():
+			return
+		case <-o.done:
+			return
+		}
+	}
+}
+
+// icyWriter inserts a metadata block with the current title after every metaint bytes.
+type icyWriter struct {
+	writer    http.ResponseWriter
+	metaint   int
+	remaining int
+	title     func() string
+	sent      string
+}
+
+func (w *icyWriter) Write(p []byte) (int, error) {
+	written := 0
+
+	for len(p) > 0 {
+		n := len(p)
+
+		if n > w.remaining {
+			n = w.remaining
+		}
+
+		n, err := w.writer.Write(p[:n])
+		written += n
+		w.remaining -= n
+		p = p[n:]
+
+		if err != nil {
+			return written, err
+		}
+
+		if w.remaining == 0 {
+			_, err = w.writer.Write(w.metadata())
+
+			if err != nil {
+				return written, err
+			}
+
+			w.remaining = w.metaint
+		}
+	}
+
+	return written, nil
+}
+
+// icyTitle prepares a title for a StreamTitle value. ICY has no escaping and players end the value at a quote, so
+// quotes are replaced by typographic ones, and the title is cut at a rune to fit into the largest metadata block.
+func icyTitle(title string) string {
+	title = strings.ReplaceAll(title, "'", "’")
+
+	// the length of the block is counted in 16 bytes, StreamTitle='' and ; take 15 of them
+	maxLength := 255*16 - len("StreamTitle='';")
+
+	if len(title) <= maxLength {
+		return title
+	}
+
+	end := maxLength
+
+	for end > 0 && !utf8.RuneStart(title[end]) {
+		end--
+	}
+
+	return title[:end]
+}
+
+// metadata returns the block with the title if it changed, otherwise an empty block.
+func (w *icyWriter) metadata() []byte {
+	title := w.title()
+
+	if title == w.sent {
+		return []byte{0}
+	}
+
+	w.sent = title
+	text := fmt.Sprintf("StreamTitle='%s';", icyTitle(title))
+
+	blocks := (len(text) + 15) / 16
+	block := make([]byte, 1+blocks*16)
+	block[0] = byte(blocks)
+	copy(block[1:], text)
+
+	return block
+}
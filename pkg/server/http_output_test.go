@@ -0,0 +1,105 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/faiface/beep"
+	"github.com/sirupsen/logrus"
+)
+
+func TestIcyWriter(t *testing.T) {
+	metadata := func(title string) string {
+		w := &icyWriter{title: func() string { return title }}
+		block := w.metadata()
+
+		if len(block) != 1+int(block[0])*16 {
+			t.Fatalf("expected %d blocks of 16 bytes, got %d bytes", block[0], len(block)-1)
+		}
+
+		return string(bytes.TrimRight(block[1:], "\x00"))
+	}
+
+	t.Run(
+		"should replace the quotes of the title",
+		func(t *testing.T) {
+			if text := metadata("Artist - It's 'quoted';"); text != "StreamTitle='Artist - It’s ’quoted’;';" {
+				t.Errorf("unexpected metadata %q", text)
+			}
+		},
+	)
+
+	t.Run(
+		"should cut long titles at a rune and keep the end of the value",
+		func(t *testing.T) {
+			text := metadata(strings.Repeat("ä", 3000))
+
+			if len(text) > 255*16 || !utf8.ValidString(text) || !strings.HasSuffix(text, "';") {
+				t.Errorf("unexpected metadata of %d bytes ending with %q", len(text), text[len(text)-4:])
+			}
+		},
+	)
+
+	t.Run(
+		"should send an empty block for an unchanged title",
+		func(t *testing.T) {
+			w := &icyWriter{title: func() string { return "Title" }}
+			w.metadata()
+
+			if block := w.metadata(); len(block) != 1 || block[0] != 0 {
+				t.Errorf("expected an empty block, got %q", block)
+			}
+		},
+	)
+}
+
+func TestHTTPOutput_Close(t *testing.T) {
+	t.Run(
+		"should end the streams of the listeners without waiting for them",
+		func(t *testing.T) {
+			logger := logrus.New()
+			logger.SetLevel(logrus.ErrorLevel)
+
+			o := newHTTPOutput(logger, "", beep.Format{SampleRate: 44100, NumChannels: 2, Precision: 2})
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			go func() {
+				_ = o.server.Serve(listener)
+			}()
+
+			response, err := http.Get("http://" + listener.Addr().String() + "/")
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer response.Body.Close()
+
+			// the header of the stream arrives once the listener is registered
+			if _, err := io.ReadFull(response.Body, make([]byte, 44)); err != nil {
+				t.Fatal(err)
+			}
+
+			closed := time.Now()
+			o.Close()
+
+			if d := time.Since(closed); d > time.Millisecond*500 {
+				t.Errorf("expected the close to return at once, it took %s", d)
+			}
+
+			if _, err := io.ReadAll(response.Body); err != nil {
+				t.Errorf("expected the stream to end, got %v", err)
+			}
+		},
+	)
+}
@@ -0,0 +1,261 @@
+package server
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/panjf2000/gnet/v2"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+
+	"network-audio/pkg/circularbuffer"
+	"network-audio/pkg/messages"
+)
+
+// Delivery decides what happens to the frames of a client that does not keep up.
+type Delivery int
+
+const (
+	// Guaranteed frames are written in order, a client whose queue of them overflows is disconnected.
+	Guaranteed Delivery = iota
+
+	// Droppable frames replace the oldest queued droppable frames of a client that is behind,
+	// e.g. audio that would arrive too late anyway.
+	Droppable
+)
+
+const (
+	// frames written at once
+	outboundBatchSize = 32
+
+	// bytes buffered by gnet for a client above which no more frames are written until it drained
+	outboundMaxPending = 256 * 1024
+
+	// audio queued for a client on top of the history burst before it is behind
+	outboundAudioDuration = time.Second * 3
+)
+
+var ErrClientBehind = errors.New("client is behind")
+
+// deliveryOf returns how the message is delivered, audio may be dropped while all other messages are guaranteed.
+func deliveryOf(msg proto.Message) Delivery {
+	if _, ok := msg.(*messages.Audio); ok {
+		return Droppable
+	}
+
+	return Guaranteed
+}
+
+// WithOutboundQueue limits the frames queued per client, droppable frames are dropped beyond their limit
+// and clients that exceed the limit of guaranteed frames are disconnected. By default the limits are derived from the
+// sample rate, see outboundLimits.
+func WithOutboundQueue(droppable int, guaranteed int) Option {
+	return func(s *Server) {
+		s.outboundDroppable = droppable
+		s.outboundGuaranteed = guaranteed
+	}
+}
+
+// WithSlowClientTimeout disconnects clients that stay behind for the timeout.
+func WithSlowClientTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.slowClientTimeout = timeout
+	}
+}
+
+// outboundConnection is the part of a gnet.Conn the outbound writes to, web clients implement it with their writer.
+type outboundConnection interface {
+	AsyncWrite(buf []byte, callback gnet.AsyncCallback) error
+	AsyncWritev(bs [][]byte, callback gnet.AsyncCallback) error
+	OutboundBuffered() int
+	Close() error
+	RemoteAddr() net.Addr
+}
+
+// outboundLimits returns the limits of the queues for blocks of the frames at the rate, the droppable frames hold the
+// history burst and outboundAudioDuration of audio, the guaranteed frames four times as many.
+func outboundLimits(rate beep.SampleRate, blockSize int, history time.Duration) (droppable int, guaranteed int) {
+	droppable = rate.N(history+outboundAudioDuration)/blockSize + 1
+
+	return droppable, droppable * 4
+}
+
+// outbound is the queue of frames to a client. Only one write is in flight at a time, so the frames are written in
+// order, and nothing is written while gnet still buffers more than outboundMaxPending bytes for the client.
+type outbound struct {
+	logger     logrus.FieldLogger
+	connection outboundConnection
+	timeout    time.Duration
+
+	droppable  *circularbuffer.Queue
+	guaranteed *circularbuffer.Queue
+	batch      []any
+
+	lock    *sync.Mutex
+	writing bool
+	// the client did not read the bytes buffered for it yet
+	blocked bool
+	closed  bool
+	// the time since when the client is behind, zero if it is not
+	behindSince time.Time
+	dropped     uint64
+}
+
+func newOutbound(logger logrus.FieldLogger, connection outboundConnection, droppable int, guaranteed int, timeout time.Duration) *outbound {
+	return &outbound{
+		logger:     logger,
+		connection: connection,
+		timeout:    timeout,
+		droppable:  circularbuffer.New(droppable, circularbuffer.WithOverwrite()),
+		guaranteed: circularbuffer.New(guaranteed),
+		batch:      make([]any, outboundBatchSize),
+		lock:       &sync.Mutex{},
+	}
+}
+
+// outboundOf returns the queue of the connection, it is created in OnOpen.
+func outboundOf(connection gnet.Conn) *outbound {
+	o, _ := connection.Context().(*outbound)
+
+	return o
+}
+
+// queue adds the frame and starts writing if no write is in flight. It returns ErrClientBehind and disconnects
+// the client if the frame is guaranteed and can not be queued or if the client stayed behind for too long.
+func (o *outbound) queue(frame []byte, delivery Delivery) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if o.closed {
+		return ErrClientBehind
+	}
+
+	switch delivery {
+	case Droppable:
+		if o.droppable.Full() {
+			o.behind()
+			o.dropped++
+
+			if o.dropped%100 == 1 {
+				o.logger.Warnf("client %s is behind, dropped %d frames", o.connection.RemoteAddr(), o.dropped)
+			}
+		}
+
+		o.droppable.TryEnqueue(frame)
+	case Guaranteed:
+		if !o.guaranteed.TryEnqueue(frame) {
+			o.disconnect("its queue is full")
+
+			return ErrClientBehind
+		}
+	}
+
+	if !o.behindSince.IsZero() && time.Since(o.behindSince) > o.timeout {
+		o.disconnect("it stayed behind for " + time.Since(o.behindSince).Truncate(time.Millisecond).String())
+
+		return ErrClientBehind
+	}
+
+	o.flush()
+
+	return nil
+}
+
+// flush writes the next batch while the lock is held, the guaranteed frames first. A blocked client gets an empty
+// write instead, to check whether it drained its buffer.
+func (o *outbound) flush() {
+	if o.writing || o.closed {
+		return
+	}
+
+	if o.blocked {
+		o.write(o.connection.AsyncWrite(nil, o.written))
+
+		return
+	}
+
+	n := o.guaranteed.TryDequeueBulk(o.batch)
+	n += o.droppable.TryDequeueBulk(o.batch[n:])
+
+	if n == 0 {
+		o.behindSince = time.Time{}
+
+		return
+	}
+
+	frames := make([][]byte, n)
+
+	for i := range frames {
+		frames[i] = o.batch[i].([]byte)
+		o.batch[i] = nil
+	}
+
+	o.write(o.connection.AsyncWritev(frames, o.written))
+}
+
+func (o *outbound) write(err error) {
+	if err != nil {
+		o.logger.Errorf("error writing to connection: %s\n", err)
+
+		return
+	}
+
+	o.writing = true
+}
+
+// written is called by the event loop of the connection, or the writer of a web client, once a write is buffered or
+// sent.
+func (o *outbound) written(gnet.Conn) error {
+	pending := o.connection.OutboundBuffered()
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.writing = false
+	o.blocked = pending > outboundMaxPending
+
+	if o.blocked {
+		o.behind()
+
+		return nil
+	}
+
+	o.flush()
+
+	return nil
+}
+
+func (o *outbound) behind() {
+	if o.behindSince.IsZero() {
+		o.behindSince = time.Now()
+	}
+}
+
+func (o *outbound) disconnect(reason string) {
+	o.logger.Warnf("disconnecting %s because %s", o.connection.RemoteAddr(), reason)
+	o.close()
+
+	err := o.connection.Close()
+
+	if err != nil {
+		o.logger.Errorf("error closing connection: %s\n", err)
+	}
+}
+
+// close stops queueing while the lock is held, the queued frames are released.
+func (o *outbound) close() {
+	o.closed = true
+	o.droppable.Clear()
+	o.guaranteed.Clear()
+}
+
+// Close stops queueing once the connection is closed.
+func (o *outbound) Close() {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.close()
+}
@@ -0,0 +1,249 @@
+package server
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/panjf2000/gnet/v2"
+	"github.com/sirupsen/logrus"
+
+	"network-audio/pkg/messages"
+	"network-audio/pkg/server/player"
+	"network-audio/pkg/timex"
+)
+
+// testConnection records the writes, the test plays the event loop by calling their callbacks.
+type testConnection struct {
+	gnet.Conn
+
+	writes    [][][]byte
+	callbacks []gnet.AsyncCallback
+	pending   int
+	closed    bool
+	context   interface{}
+}
+
+func (c *testConnection) Context() interface{} {
+	return c.context
+}
+
+func (c *testConnection) SetContext(context interface{}) {
+	c.context = context
+}
+
+func (c *testConnection) AsyncWrite(buf []byte, callback gnet.AsyncCallback) error {
+	return c.AsyncWritev([][]byte{buf}, callback)
+}
+
+func (c *testConnection) AsyncWritev(bs [][]byte, callback gnet.AsyncCallback) error {
+	c.writes = append(c.writes, bs)
+	c.callbacks = append(c.callbacks, callback)
+
+	return nil
+}
+
+func (c *testConnection) OutboundBuffered() int {
+	return c.pending
+}
+
+func (c *testConnection) Close() error {
+	c.closed = true
+
+	return nil
+}
+
+func (c *testConnection) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1234}
+}
+
+// complete runs the callbacks of the writes in flight.
+func (c *testConnection) complete() {
+	callbacks := c.callbacks
+	c.callbacks = nil
+
+	for _, callback := range callbacks {
+		_ = callback(c)
+	}
+}
+
+func (c *testConnection) written() []string {
+	var frames []string
+
+	for _, write := range c.writes {
+		for _, frame := range write {
+			if frame != nil {
+				frames = append(frames, string(frame))
+			}
+		}
+	}
+
+	return frames
+}
+
+func testOutbound(connection *testConnection, timeout time.Duration) *outbound {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	return newOutbound(logger, connection, 2, 2, timeout)
+}
+
+func TestOutbound(t *testing.T) {
+	t.Run(
+		"should write one batch at a time with the guaranteed frames first",
+		func(t *testing.T) {
+			connection := &testConnection{}
+			o := testOutbound(connection, time.Second)
+
+			_ = o.queue([]byte("a1"), Droppable)
+			_ = o.queue([]byte("a2"), Droppable)
+			_ = o.queue([]byte("c1"), Guaranteed)
+
+			if len(connection.writes) != 1 {
+				t.Fatalf("expected 1 write in flight, got %d", len(connection.writes))
+			}
+
+			connection.complete()
+
+			expected := []string{"a1", "c1", "a2"}
+			written := connection.written()
+
+			if len(written) != len(expected) {
+				t.Fatalf("expected %v, got %v", expected, written)
+			}
+
+			for i := range expected {
+				if written[i] != expected[i] {
+					t.Fatalf("expected %v, got %v", expected, written)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"should drop the oldest audio while the client is blocked",
+		func(t *testing.T) {
+			connection := &testConnection{pending: outboundMaxPending + 1}
+			o := testOutbound(connection, time.Second)
+
+			_ = o.queue([]byte("a1"), Droppable)
+			connection.complete()
+
+			for _, frame := range []string{"a2", "a3", "a4"} {
+				_ = o.queue([]byte(frame), Droppable)
+				connection.complete()
+			}
+
+			if o.dropped != 1 {
+				t.Errorf("expected 1 dropped frame, got %d", o.dropped)
+			}
+
+			connection.pending = 0
+			_ = o.queue([]byte("c1"), Guaranteed)
+			connection.complete()
+			connection.complete()
+
+			expected := []string{"a1", "c1", "a3", "a4"}
+			written := connection.written()
+
+			if len(written) != len(expected) {
+				t.Fatalf("expected %v, got %v", expected, written)
+			}
+
+			for i := range expected {
+				if written[i] != expected[i] {
+					t.Fatalf("expected %v, got %v", expected, written)
+				}
+			}
+
+			if !o.behindSince.IsZero() {
+				t.Error("expected the client to have caught up")
+			}
+		},
+	)
+
+	t.Run(
+		"should disconnect a client that can not take guaranteed frames",
+		func(t *testing.T) {
+			connection := &testConnection{pending: outboundMaxPending + 1}
+			o := testOutbound(connection, time.Minute)
+
+			_ = o.queue([]byte("c1"), Guaranteed)
+			connection.complete()
+
+			for _, frame := range []string{"c2", "c3"} {
+				if err := o.queue([]byte(frame), Guaranteed); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := o.queue([]byte("c4"), Guaranteed); err != ErrClientBehind {
+				t.Errorf("expected ErrClientBehind, got %v", err)
+			}
+
+			if !connection.closed {
+				t.Error("expected the connection to be closed")
+			}
+		},
+	)
+
+	t.Run(
+		"should disconnect a client that stays behind",
+		func(t *testing.T) {
+			connection := &testConnection{pending: outboundMaxPending + 1}
+			o := testOutbound(connection, time.Millisecond*10)
+
+			_ = o.queue([]byte("a1"), Droppable)
+			connection.complete()
+
+			if connection.closed {
+				t.Fatal("expected the connection to stay open")
+			}
+
+			time.Sleep(time.Millisecond * 20)
+
+			if err := o.queue([]byte("a2"), Droppable); err != ErrClientBehind {
+				t.Errorf("expected ErrClientBehind, got %v", err)
+			}
+
+			if !connection.closed {
+				t.Error("expected the connection to be closed")
+			}
+		},
+	)
+}
+
+func TestOutboundLimits(t *testing.T) {
+	t.Run(
+		"should fit the history burst of a high sample rate into the queue",
+		func(t *testing.T) {
+			logger := logrus.New()
+			logger.SetLevel(logrus.ErrorLevel)
+
+			format := beep.Format{SampleRate: 96000, NumChannels: 2, Precision: 2}
+			s := New(logger, ":0", WithPlayerOptions(player.WithFormat(format)), WithHistory(time.Second*2))
+			start := time.Now()
+
+			// the history of 2 seconds in blocks of 512 frames
+			for i := 0; i < format.SampleRate.N(time.Second*2)/512; i++ {
+				m := messages.NewAudio(messages.ChannelLayout_CHANNEL_LAYOUT_MONO, make([]float64, 512))
+				m.SampleRate = uint32(format.SampleRate)
+				m.Time = timex.ToTimestamp(start.Add(format.SampleRate.D(i * 512)))
+
+				if err := s.Send(m); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			connection := &testConnection{}
+			s.OnOpen(connection)
+
+			o := outboundOf(connection)
+
+			if o.dropped > 0 || !o.behindSince.IsZero() {
+				t.Errorf("expected the burst to fit, %d of %d frames were dropped", o.dropped, len(s.history.Pending(time.Now())))
+			}
+		},
+	)
+}
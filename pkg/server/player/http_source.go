@@ -0,0 +1,330 @@
+package player
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/mp3"
+	"github.com/faiface/beep/vorbis"
+	"github.com/pkg/errors"
+
+	"network-audio/pkg/metadata"
+	"network-audio/pkg/ulidx"
+)
+
+const (
+	httpReconnectInterval = time.Second
+	httpReconnectMaxTimes = 5
+)
+
+// isURL returns whether the path of a playlist entry is an HTTP stream.
+func isURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// httpStream relays an internet radio or any HTTP MP3/Ogg stream. It reconnects if the stream stalls or ends.
+type httpStream struct {
+	player *Player
+	url    string
+	client *http.Client
+
+	input    *inputBuffer
+	decoder  beep.StreamCloser
+	streamer beep.Streamer
+
+	// the title is only announced when it changes, stations repeat it in every metadata block
+	title   string
+	metaint int
+	onTitle func(title string)
+	err     error
+
+	// the reconnect running in the background, the stream plays silence until it is done
+	reconnection chan reconnection
+	attempts     int
+	silence      int
+}
+
+type reconnection struct {
+	response *http.Response
+	err      error
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func (p *Player) openHTTPStream(url string, onTitle func(title string)) (*httpStream, error) {
+	s := &httpStream{
+		player: p,
+		url:    url,
+		client: &http.Client{
+			Transport: &http.Transport{
+				Proxy:                 http.ProxyFromEnvironment,
+				ResponseHeaderTimeout: p.stallTimeout,
+			},
+		},
+		onTitle: onTitle,
+	}
+
+	err := s.connect()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *httpStream) connect() error {
+	response, err := s.request()
+
+	if err != nil {
+		return err
+	}
+
+	return s.open(response)
+}
+
+// request requests the stream, it is safe to call while the stream is played.
+func (s *httpStream) request() (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodGet, s.url, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Icy-MetaData", "1")
+
+	response, err := s.client.Do(request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		_ = response.Body.Close()
+
+		return nil, errors.Errorf("unexpected status %s", response.Status)
+	}
+
+	return response, nil
+}
+
+// open starts to decode the response.
+func (s *httpStream) open(response *http.Response) error {
+	var err error
+
+	metaint, _ := strconv.Atoi(response.Header.Get("icy-metaint"))
+
+	input := newInputBuffer(response.Body, s.player.httpBufferSize, s.player.stallTimeout)
+	reader := readCloser{newIcyReader(input, metaint, s.setTitle), input}
+
+	var decoder beep.StreamSeekCloser
+	var format beep.Format
+
+	if strings.Contains(response.Header.Get("Content-Type"), "ogg") || strings.HasSuffix(s.url, ".ogg") {
+		decoder, format, err = vorbis.Decode(reader)
+	} else {
+		decoder, format, err = mp3.Decode(reader)
+	}
+
+	if err != nil {
+		_ = input.Close()
+
+		return err
+	}
+
+	s.input = input
+	s.metaint = metaint
+	s.decoder = decoder
+	s.streamer = decoder
+
+	if format.SampleRate != s.player.format.SampleRate {
+		s.streamer = beep.Resample(s.player.resampleQuality, format.SampleRate, s.player.format.SampleRate, decoder)
+	}
+
+	return nil
+}
+
+func (s *httpStream) setTitle(title string) {
+	if title == s.title {
+		return
+	}
+
+	s.title = title
+
+	if s.onTitle != nil {
+		s.onTitle(title)
+	}
+}
+
+// reconnect requests the stream again in the background after the reconnect interval.
+func (s *httpStream) reconnect() {
+	result := make(chan reconnection, 1)
+
+	s.reconnection = result
+	s.attempts++
+
+	go func() {
+		time.Sleep(httpReconnectInterval)
+
+		response, err := s.request()
+		result <- reconnection{response: response, err: err}
+	}()
+}
+
+// reconnected opens the stream of the reconnect, it returns false if the stream is given up.
+func (s *httpStream) reconnected(r reconnection) bool {
+	err := r.err
+
+	if err == nil {
+		err = s.open(r.response)
+	}
+
+	if err == nil {
+		s.player.logger.Infof("reconnected to %s after %s of silence", s.url, s.player.format.SampleRate.D(s.silence))
+
+		s.attempts = 0
+		s.silence = 0
+
+		return true
+	}
+
+	s.err = err
+	s.player.logger.Warnf("reconnect %d/%d to %s failed: %s", s.attempts, httpReconnectMaxTimes, s.url, err)
+
+	if s.attempts >= httpReconnectMaxTimes {
+		return false
+	}
+
+	s.reconnect()
+
+	return true
+}
+
+func (s *httpStream) Stream(samples [][2]float64) (n int, ok bool) {
+	for n < len(samples) {
+		if s.reconnection != nil {
+			select {
+			case r := <-s.reconnection:
+				s.reconnection = nil
+
+				if !s.reconnected(r) {
+					return n, n > 0
+				}
+
+				continue
+			default:
+			}
+
+			// the clients play silence instead of running dry while the stream reconnects
+			for i := n; i < len(samples); i++ {
+				samples[i] = [2]float64{}
+			}
+
+			s.silence += len(samples) - n
+
+			return len(samples), true
+		}
+
+		if s.streamer == nil {
+			break
+		}
+
+		read, ok := s.streamer.Stream(samples[n:])
+		n += read
+
+		if ok && read > 0 {
+			continue
+		}
+
+		s.player.logger.Warnf("stream %s interrupted, playing silence while reconnecting: %v", s.url, s.decoder.Err())
+
+		s.closeDecoder()
+		s.reconnect()
+	}
+
+	return n, n > 0
+}
+
+func (s *httpStream) Err() error {
+	return s.err
+}
+
+func (s *httpStream) closeDecoder() {
+	if s.decoder != nil {
+		_ = s.decoder.Close()
+	}
+
+	s.decoder = nil
+	s.streamer = nil
+}
+
+func (s *httpStream) Close() error {
+	s.closeDecoder()
+
+	if s.reconnection != nil {
+		go func(result chan reconnection) {
+			if r := <-result; r.response != nil {
+				_ = r.response.Body.Close()
+			}
+		}(s.reconnection)
+
+		s.reconnection = nil
+	}
+
+	return nil
+}
+
+// openHTTPTrack opens a stream as track of unknown length. Streams with titles are announced with their first title
+// and again on every new one.
+func (p *Player) openHTTPTrack(url string) (*track, error) {
+	t := &track{
+		id:     ulidx.MustNew().String(),
+		path:   url,
+		length: -1,
+		gain:   1,
+	}
+
+	stream, err := p.openHTTPStream(
+		url,
+		func(title string) {
+			p.streamTitle(t, title)
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	t.streamer = stream
+	t.source = stream
+	t.awaitTitle = stream.metaint > 0
+
+	return t, nil
+}
+
+// streamTitle announces a new title of a stream as new track.
+func (p *Player) streamTitle(t *track, title string) {
+	m := &metadata.Metadata{Title: title, Tags: map[string]string{}}
+
+	if artist, name, found := strings.Cut(title, " - "); found {
+		m.Artist = artist
+		m.Title = name
+	}
+
+	p.logger.Infof("stream title of %s: %s", t.path, title)
+
+	t.id = ulidx.MustNew().String()
+	t.metadata = m
+
+	// a title that arrives before the track started is announced with its start
+	if t.started {
+		p.startTrack(t)
+	}
+}
@@ -0,0 +1,225 @@
+package player
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+
+	"network-audio/pkg/messages"
+)
+
+const testMetaint = 8192
+
+type recordingTarget struct {
+	lock     *sync.Mutex
+	messages []proto.Message
+}
+
+func (t *recordingTarget) Send(msg proto.Message) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.messages = append(t.messages, msg)
+
+	return nil
+}
+
+func (t *recordingTarget) titles() []string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var titles []string
+
+	for _, msg := range t.messages {
+		if m, ok := msg.(*messages.NowPlaying); ok {
+			titles = append(titles, m.Artist+"|"+m.Title)
+		}
+	}
+
+	return titles
+}
+
+// icyBody interleaves the audio with a metadata block every testMetaint bytes.
+func icyBody(audio []byte, title string) []byte {
+	body := &bytes.Buffer{}
+
+	for offset := 0; offset < len(audio); offset += testMetaint {
+		end := offset + testMetaint
+
+		if end > len(audio) {
+			end = len(audio)
+		}
+
+		body.Write(audio[offset:end])
+
+		if end-offset < testMetaint {
+			break
+		}
+
+		metadata := []byte(fmt.Sprintf("StreamTitle='%s';", title))
+		blocks := (len(metadata) + 15) / 16
+		body.WriteByte(byte(blocks))
+		body.Write(metadata)
+		body.Write(make([]byte, blocks*16-len(metadata)))
+	}
+
+	return body.Bytes()
+}
+
+func TestParseStreamTitle(t *testing.T) {
+	t.Run(
+		"should parse a title with quotes",
+		func(t *testing.T) {
+			title, ok := parseStreamTitle("StreamTitle='Artist - It's a Title';StreamUrl='';")
+
+			if !ok || title != "Artist - It's a Title" {
+				t.Errorf("expected title to be parsed, got %q, %v", title, ok)
+			}
+		},
+	)
+
+	t.Run(
+		"should return no title without a stream title",
+		func(t *testing.T) {
+			if _, ok := parseStreamTitle("StreamUrl='';"); ok {
+				t.Errorf("expected no title")
+			}
+		},
+	)
+}
+
+func TestHTTPSourceReconnect(t *testing.T) {
+	audio, err := os.ReadFile("../../../test/audio.mp3")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	connections := int32(0)
+
+	server := httptest.NewServer(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				connection := atomic.AddInt32(&connections, 1)
+				body := icyBody(audio, fmt.Sprintf("Radio - Song %d", connection))
+
+				if r.Header.Get("Icy-MetaData") != "1" {
+					t.Errorf("expected metadata to be requested")
+				}
+
+				w.Header().Set("Content-Type", "audio/mpeg")
+				w.Header().Set("icy-metaint", fmt.Sprint(testMetaint))
+
+				// the first connection drops after a part of the stream
+				if connection == 1 {
+					body = body[:len(body)/8]
+				}
+
+				_, _ = w.Write(body)
+			},
+		),
+	)
+	defer server.Close()
+
+	target := &recordingTarget{lock: &sync.Mutex{}}
+	logger := logrus.New()
+	logger.SetOutput(&bytes.Buffer{})
+
+	p := New(target, logger, WithHTTPBuffer(64*1024, time.Millisecond*500))
+
+	tr, err := p.openTrack(server.URL + "/stream")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer tr.Close()
+
+	p.startTrack(tr)
+
+	if tr.remaining() <= 0 || tr.duration(p.format) != 0 {
+		t.Errorf("expected a live track of unknown length")
+	}
+
+	// read more than the first connection delivers, the reconnect is filled with silence
+	stream := tr.source.(*httpStream)
+	samples := make([][2]float64, 4096)
+	total := 0
+	silence := 0
+	limit := p.format.SampleRate.N(time.Second * 20)
+	deadline := time.Now().Add(time.Second * 10)
+
+	for total-silence < limit && time.Now().Before(deadline) {
+		before := stream.silence
+		n, ok := tr.Stream(samples)
+		total += n
+
+		if stream.silence > before {
+			silence += stream.silence - before
+
+			// the test reads faster than real time, it waits for the reconnect instead
+			time.Sleep(time.Millisecond * 10)
+		}
+
+		if !ok {
+			break
+		}
+	}
+
+	if total-silence < limit {
+		t.Errorf("expected the stream to continue after reconnecting, got %d of %d samples", total-silence, limit)
+	}
+
+	if silence == 0 {
+		t.Errorf("expected silence while reconnecting")
+	}
+
+	if atomic.LoadInt32(&connections) < 2 {
+		t.Errorf("expected a reconnect, got %d connections", connections)
+	}
+
+	titles := target.titles()
+
+	if len(titles) != 2 || titles[0] != "Radio|Song 1" || titles[1] != "Radio|Song 2" {
+		t.Errorf("unexpected stream titles %v", titles)
+	}
+}
+
+func TestInputBuffer(t *testing.T) {
+	t.Run(
+		"should fail every read without data within the stall timeout",
+		func(t *testing.T) {
+			reader, writer := io.Pipe()
+			b := newInputBuffer(reader, 1024, time.Millisecond*20)
+			defer b.Close()
+
+			buffer := make([]byte, 16)
+
+			for i := 0; i < 3; i++ {
+				if _, err := b.Read(buffer); err != ErrStalled {
+					t.Fatalf("expected ErrStalled, got %v", err)
+				}
+			}
+
+			go func() {
+				_, _ = writer.Write([]byte("data"))
+			}()
+
+			n, err := b.Read(buffer)
+
+			if err != nil || string(buffer[:n]) != "data" {
+				t.Fatalf("expected the data after the stall, got %q, %v", buffer[:n], err)
+			}
+		},
+	)
+}
@@ -0,0 +1,102 @@
+package player
+
+import (
+	"io"
+	"strings"
+)
+
+// icyReader strips the ICY metadata blocks of a Shoutcast/Icecast stream and reports the stream titles.
+type icyReader struct {
+	reader io.Reader
+
+	// bytes of audio between two metadata blocks, 0 if the stream has no metadata
+	metaint   int
+	remaining int
+
+	onTitle func(title string)
+}
+
+func newIcyReader(reader io.Reader, metaint int, onTitle func(title string)) *icyReader {
+	return &icyReader{
+		reader:    reader,
+		metaint:   metaint,
+		remaining: metaint,
+		onTitle:   onTitle,
+	}
+}
+
+func (r *icyReader) Read(p []byte) (int, error) {
+	if r.metaint <= 0 {
+		return r.reader.Read(p)
+	}
+
+	if r.remaining == 0 {
+		err := r.readMetadata()
+
+		if err != nil {
+			return 0, err
+		}
+
+		r.remaining = r.metaint
+	}
+
+	if len(p) > r.remaining {
+		p = p[:r.remaining]
+	}
+
+	n, err := r.reader.Read(p)
+	r.remaining -= n
+
+	return n, err
+}
+
+// readMetadata reads a metadata block, its length is given in 16 byte units by the first byte.
+func (r *icyReader) readMetadata() error {
+	length := make([]byte, 1)
+
+	if _, err := io.ReadFull(r.reader, length); err != nil {
+		return err
+	}
+
+	if length[0] == 0 {
+		return nil
+	}
+
+	block := make([]byte, int(length[0])*16)
+
+	if _, err := io.ReadFull(r.reader, block); err != nil {
+		return err
+	}
+
+	title, ok := parseStreamTitle(strings.TrimRight(string(block), "\x00"))
+
+	if ok && r.onTitle != nil {
+		r.onTitle(title)
+	}
+
+	return nil
+}
+
+// parseStreamTitle extracts the title of metadata like "StreamTitle='Artist - Title';StreamUrl=”;".
+func parseStreamTitle(metadata string) (string, bool) {
+	const key = "StreamTitle='"
+
+	start := strings.Index(metadata, key)
+
+	if start < 0 {
+		return "", false
+	}
+
+	value := metadata[start+len(key):]
+	end := strings.Index(value, "';")
+
+	if end < 0 {
+		end = strings.LastIndex(value, "'")
+	}
+
+	if end < 0 {
+		return "", false
+	}
+
+	return value[:end], true
+}
@@ -0,0 +1,118 @@
+package player
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const inputChunkSize = 4096
+
+var ErrStalled = errors.New("input stalled")
+
+// inputBuffer reads a network stream in the background, so short network hiccups do not reach the decoder.
+// Reads fail with ErrStalled if no data arrived within the stall timeout.
+type inputBuffer struct {
+	body   io.ReadCloser
+	chunks chan []byte
+	err    error
+
+	current []byte
+	stall   time.Duration
+	// reused by every read that waits for a chunk
+	timer *time.Timer
+
+	done      chan struct{}
+	closeOnce *sync.Once
+}
+
+func newInputBuffer(body io.ReadCloser, size int, stall time.Duration) *inputBuffer {
+	b := &inputBuffer{
+		body:      body,
+		chunks:    make(chan []byte, size/inputChunkSize+1),
+		stall:     stall,
+		done:      make(chan struct{}),
+		closeOnce: &sync.Once{},
+	}
+
+	go b.fill()
+
+	return b
+}
+
+func (b *inputBuffer) fill() {
+	defer close(b.chunks)
+
+	for {
+		chunk := make([]byte, inputChunkSize)
+		n, err := b.body.Read(chunk)
+
+		if n > 0 {
+			select {
+			case b.chunks <- chunk[:n]:
+			case <-b.done:
+				return
+			}
+		}
+
+		if err != nil {
+			b.err = err
+			return
+		}
+	}
+}
+
+func (b *inputBuffer) Read(p []byte) (int, error) {
+	if len(b.current) == 0 {
+		select {
+		case chunk, ok := <-b.chunks:
+			if !ok {
+				return 0, b.err
+			}
+
+			b.current = chunk
+		case <-b.waitStall():
+			return 0, ErrStalled
+		}
+	}
+
+	n := copy(p, b.current)
+	b.current = b.current[n:]
+
+	return n, nil
+}
+
+// waitStall restarts the stall timer and returns its channel.
+func (b *inputBuffer) waitStall() <-chan time.Time {
+	if b.timer == nil {
+		b.timer = time.NewTimer(b.stall)
+
+		return b.timer.C
+	}
+
+	if !b.timer.Stop() {
+		select {
+		case <-b.timer.C:
+		default:
+		}
+	}
+
+	b.timer.Reset(b.stall)
+
+	return b.timer.C
+}
+
+func (b *inputBuffer) Close() error {
+	err := error(nil)
+
+	b.closeOnce.Do(
+		func() {
+			close(b.done)
+			err = b.body.Close()
+		},
+	)
+
+	return err
+}
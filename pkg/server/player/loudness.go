@@ -0,0 +1,264 @@
+package player
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"network-audio/pkg/dsp"
+	"network-audio/pkg/metadata"
+)
+
+const (
+	// replayGainReference is the loudness ReplayGain 2.0 normalizes to
+	replayGainReference = -18.0
+
+	// truePeakCeiling is the maximum true peak of the normalized stream in dBTP
+	truePeakCeiling = -1.0
+)
+
+// loudness is the measured loudness of a file.
+type loudness struct {
+	// integrated loudness in LUFS
+	Integrated float64 `json:"integrated"`
+	// linear true peak
+	Peak float64 `json:"peak"`
+	// the track has no audio above the absolute gate, its integrated loudness is 0 and it is not normalized
+	Silent bool `json:"silent,omitempty"`
+
+	// identify changed files
+	Size    int64 `json:"size"`
+	ModTime int64 `json:"modTime"`
+}
+
+// loudnessCache stores the analysis of files, optionally persisted to a JSON file.
+type loudnessCache struct {
+	path    string
+	lock    *sync.RWMutex
+	entries map[string]*loudness
+
+	// files that are being analyzed
+	pending map[string]bool
+}
+
+func newLoudnessCache(path string) *loudnessCache {
+	c := &loudnessCache{
+		path:    path,
+		lock:    &sync.RWMutex{},
+		entries: map[string]*loudness{},
+		pending: map[string]bool{},
+	}
+
+	if path == "" {
+		return c
+	}
+
+	data, err := os.ReadFile(path)
+
+	if err == nil {
+		_ = json.Unmarshal(data, &c.entries)
+	}
+
+	return c
+}
+
+func (c *loudnessCache) get(filePath string) (*loudness, bool) {
+	info, err := os.Stat(filePath)
+
+	if err != nil {
+		return nil, false
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	l, ok := c.entries[filePath]
+
+	if !ok || l.Size != info.Size() || l.ModTime != info.ModTime().UnixNano() {
+		return nil, false
+	}
+
+	return l, true
+}
+
+func (c *loudnessCache) set(filePath string, l *loudness) error {
+	info, err := os.Stat(filePath)
+
+	if err != nil {
+		return err
+	}
+
+	// JSON can not encode -Inf, one of them would break the saving of the cache for good
+	if math.IsInf(l.Integrated, 0) || math.IsNaN(l.Integrated) || math.IsInf(l.Peak, 0) || math.IsNaN(l.Peak) {
+		return errors.New("loudness is not finite")
+	}
+
+	l.Size = info.Size()
+	l.ModTime = info.ModTime().UnixNano()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.entries[filePath] = l
+
+	if c.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(c.entries)
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.path, data, 0644)
+}
+
+// startAnalysis marks the file as pending, it returns false if it is already analyzed or pending.
+func (c *loudnessCache) startAnalysis(filePath string) bool {
+	if _, ok := c.get(filePath); ok {
+		return false
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.pending[filePath] {
+		return false
+	}
+
+	c.pending[filePath] = true
+
+	return true
+}
+
+func (c *loudnessCache) finishAnalysis(filePath string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.pending, filePath)
+}
+
+// analyze measures the loudness of the files in the background, files in the cache are skipped.
+func (p *Player) analyze(filePaths []string) {
+	if p.loudnessCache == nil {
+		return
+	}
+
+	go func() {
+		for _, filePath := range filePaths {
+			p.analyzeFile(filePath)
+		}
+	}()
+}
+
+// analyzeFile measures the loudness of the file and stores it in the cache, unless it is a stream, already in the
+// cache or being analyzed.
+func (p *Player) analyzeFile(filePath string) {
+	if isURL(filePath) || !p.loudnessCache.startAnalysis(filePath) {
+		return
+	}
+
+	defer p.loudnessCache.finishAnalysis(filePath)
+
+	l, err := p.measureLoudness(filePath)
+
+	if err == nil {
+		if l.Silent {
+			p.logger.Infof("loudness of %s: silent", filePath)
+		} else {
+			p.logger.Infof("loudness of %s: %.1f LUFS, peak %.1f dBTP", filePath, l.Integrated, dsp.ToDecibel(l.Peak))
+		}
+
+		err = p.loudnessCache.set(filePath, l)
+	}
+
+	if err != nil {
+		p.logger.Warnf("analyze loudness of %s error: %s", filePath, err)
+	}
+}
+
+func (p *Player) measureLoudness(filePath string) (*loudness, error) {
+	source, format, err := p.getFileStream(filePath)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer source.Close()
+
+	meter := dsp.NewLoudnessMeter(format.SampleRate)
+	buffer := make([][2]float64, 4096)
+
+	for {
+		n, ok := source.Stream(buffer)
+		meter.Write(buffer[:n])
+
+		if !ok {
+			break
+		}
+	}
+
+	if err := source.Err(); err != nil {
+		return nil, err
+	}
+
+	l := &loudness{Integrated: meter.Integrated(), Peak: meter.TruePeak()}
+
+	if math.IsInf(l.Integrated, -1) {
+		l.Integrated = 0
+		l.Silent = true
+	}
+
+	return l, nil
+}
+
+// normalizationGain returns the linear gain that brings the track to the target loudness.
+// ReplayGain tags are preferred over the analysis, tracks without either are not changed. The gain of an analyzed
+// track is capped so its true peak stays below the ceiling, the limiter does not have to squash a quiet track.
+func (p *Player) normalizationGain(filePath string, m *metadata.Metadata) float64 {
+	if m != nil {
+		if gain, ok := replayGain(m.Tags["REPLAYGAIN_TRACK_GAIN"]); ok {
+			return dsp.FromDecibel(gain + p.loudnessTarget - replayGainReference)
+		}
+	}
+
+	if isURL(filePath) {
+		return 1
+	}
+
+	if l, ok := p.loudnessCache.get(filePath); ok {
+		if l.Silent {
+			return 1
+		}
+
+		gain := p.loudnessTarget - l.Integrated
+
+		if l.Peak > 0 {
+			gain = math.Min(gain, truePeakCeiling-dsp.ToDecibel(l.Peak))
+		}
+
+		return dsp.FromDecibel(gain)
+	}
+
+	p.logger.Warnf("no loudness information for %s yet, playing without normalization", filePath)
+
+	return 1
+}
+
+// replayGain parses a gain tag like "-6.48 dB".
+func replayGain(value string) (float64, bool) {
+	fields := strings.Fields(value)
+
+	if len(fields) == 0 {
+		return 0, false
+	}
+
+	gain, err := strconv.ParseFloat(fields[0], 64)
+
+	return gain, err == nil
+}
@@ -0,0 +1,217 @@
+package player
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+
+	"network-audio/pkg/dsp"
+	"network-audio/pkg/metadata"
+)
+
+func testTrackFile(t *testing.T, name string) string {
+	filePath := filepath.Join(t.TempDir(), name)
+
+	if err := os.WriteFile(filePath, []byte("audio"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return filePath
+}
+
+// testAudioFile writes the start of the test audio, a short track that is decoded quickly.
+func testAudioFile(t *testing.T, name string) string {
+	data, err := os.ReadFile("../../../test/audio.mp3")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filePath := filepath.Join(t.TempDir(), name)
+
+	if err := os.WriteFile(filePath, data[:32*1024], 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return filePath
+}
+
+func TestLoudnessCache(t *testing.T) {
+	t.Run(
+		"should keep saving after a silent track",
+		func(t *testing.T) {
+			cachePath := filepath.Join(t.TempDir(), "loudness.json")
+			cache := newLoudnessCache(cachePath)
+			silent := testTrackFile(t, "silent.mp3")
+			loud := testTrackFile(t, "loud.mp3")
+
+			if err := cache.set(silent, &loudness{Silent: true}); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := cache.set(loud, &loudness{Integrated: -14, Peak: 0.9}); err != nil {
+				t.Fatal(err)
+			}
+
+			loaded := newLoudnessCache(cachePath)
+
+			if l, ok := loaded.get(silent); !ok || !l.Silent {
+				t.Errorf("expected the silent track in the saved cache, got %v", l)
+			}
+
+			if l, ok := loaded.get(loud); !ok || l.Integrated != -14 {
+				t.Errorf("expected the loud track in the saved cache, got %v", l)
+			}
+		},
+	)
+
+	t.Run(
+		"should reject loudness that is not finite",
+		func(t *testing.T) {
+			cache := newLoudnessCache(filepath.Join(t.TempDir(), "loudness.json"))
+			filePath := testTrackFile(t, "track.mp3")
+
+			if err := cache.set(filePath, &loudness{Integrated: math.Inf(-1)}); err == nil {
+				t.Error("expected an error")
+			}
+
+			if _, ok := cache.get(filePath); ok {
+				t.Error("expected the track not to be cached")
+			}
+		},
+	)
+}
+
+func TestNormalizationGain(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	p := New(nil, logger, WithLoudnessNormalization(-23, ""))
+	analyzed := testTrackFile(t, "analyzed.mp3")
+	silent := testTrackFile(t, "silent.mp3")
+	quiet := testTrackFile(t, "quiet.mp3")
+
+	_ = p.loudnessCache.set(analyzed, &loudness{Integrated: -14, Peak: 1})
+	_ = p.loudnessCache.set(silent, &loudness{Silent: true})
+	_ = p.loudnessCache.set(quiet, &loudness{Integrated: -40, Peak: 0.5})
+
+	tagged := &metadata.Metadata{Tags: map[string]string{"REPLAYGAIN_TRACK_GAIN": "-6.5 dB"}}
+
+	tests := []struct {
+		name     string
+		filePath string
+		metadata *metadata.Metadata
+		// expected gain in dB
+		expected float64
+	}{
+		{
+			name:     "should prefer the ReplayGain tag over the analysis",
+			filePath: analyzed,
+			metadata: tagged,
+			// the tag brings the track to -18 LUFS, 5 dB above the target
+			expected: -11.5,
+		},
+		{
+			name:     "should use the ReplayGain tag of streams",
+			filePath: "http://example.com/stream",
+			metadata: tagged,
+			expected: -11.5,
+		},
+		{
+			name:     "should use the analysis without a ReplayGain tag",
+			filePath: analyzed,
+			metadata: &metadata.Metadata{Tags: map[string]string{"REPLAYGAIN_TRACK_GAIN": "invalid"}},
+			expected: -9,
+		},
+		{
+			name:     "should cap the gain at the true peak ceiling",
+			filePath: quiet,
+			// 17 dB would bring the peak of -6 dBTP above the ceiling
+			expected: truePeakCeiling - dsp.ToDecibel(0.5),
+		},
+		{
+			name:     "should not change silent tracks",
+			filePath: silent,
+		},
+		{
+			name:     "should not change tracks that are not analyzed",
+			filePath: testTrackFile(t, "new.mp3"),
+		},
+		{
+			name:     "should not change streams without a ReplayGain tag",
+			filePath: "http://example.com/stream",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(
+			test.name,
+			func(t *testing.T) {
+				gain := dsp.ToDecibel(p.normalizationGain(test.filePath, test.metadata))
+
+				if math.Abs(gain-test.expected) > 1e-9 {
+					t.Errorf("expected a gain of %.2f dB, got %.2f dB", test.expected, gain)
+				}
+			},
+		)
+	}
+}
+
+func TestLoudnessAnalysis(t *testing.T) {
+	t.Run(
+		"should analyze the first file before it plays",
+		func(t *testing.T) {
+			logger, hook := test.NewNullLogger()
+			target := &recordingTarget{lock: &sync.Mutex{}}
+			clock := &testClock{now: time.Unix(1000, 0)}
+			filePath := testAudioFile(t, "first.mp3")
+
+			p := New(target, logger, WithClock(clock), WithLoudnessNormalization(-23, ""))
+
+			if err := p.PlayFiles([]string{filePath}, false); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, entry := range hook.AllEntries() {
+				if strings.HasPrefix(entry.Message, "no loudness information") {
+					t.Errorf("expected the first file to be normalized, got %q", entry.Message)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"should analyze the next entry when a track opens",
+		func(t *testing.T) {
+			logger := logrus.New()
+			logger.SetLevel(logrus.ErrorLevel)
+
+			p := New(&recordingTarget{lock: &sync.Mutex{}}, logger, WithLoudnessNormalization(-23, ""))
+			next := testAudioFile(t, "next.mp3")
+			pl := newPlaylist(p, []string{testAudioFile(t, "current.mp3"), next}, false)
+			defer pl.Close()
+
+			pl.current = pl.open()
+			deadline := time.Now().Add(time.Second * 5)
+
+			for {
+				if _, ok := p.loudnessCache.get(next); ok {
+					break
+				}
+
+				if time.Now().After(deadline) {
+					t.Fatal("expected the next entry to be analyzed")
+				}
+
+				time.Sleep(time.Millisecond * 10)
+			}
+		},
+	)
+}
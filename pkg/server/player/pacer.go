@@ -0,0 +1,121 @@
+package player
+
+import (
+	"time"
+
+	"github.com/faiface/beep"
+)
+
+// lateBlockTolerance is the time a block may be sent late without a warning, e.g. because of the scheduler.
+const lateBlockTolerance = time.Millisecond * 20
+
+// Clock is the time source of the player, it is replaced in tests.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// WithClock replaces the system clock of the player.
+func WithClock(clock Clock) Option {
+	return func(p *Player) {
+		p.clock = clock
+	}
+}
+
+// pacer schedules the blocks of a stream against an absolute schedule. The play time of a block is derived from the
+// frames scheduled since the start of the schedule, so neither rounding nor oversleeping accumulates. Blocks are sent
+// the presentation delay before they play, a pacer that fell behind sends the following blocks without waiting until
+// it caught up. Only a block that is sent after its play time by more than the tolerance restarts the schedule, so the
+// schedule holds without a presentation delay, where every block is sent at its play time.
+type pacer struct {
+	clock Clock
+	rate  beep.SampleRate
+	delay time.Duration
+
+	// the play time of the first frame and the frames scheduled since
+	start  time.Time
+	frames uint64
+}
+
+// blockSchedule is the play time of a block and how late it was sent.
+type blockSchedule struct {
+	playAt time.Time
+
+	// the time the block was sent after it should have been, zero if it was sent in time
+	late time.Duration
+
+	// the pacer fell behind by more than the presentation delay and started a new schedule
+	restarted bool
+}
+
+func newPacer(clock Clock, rate beep.SampleRate, delay time.Duration) *pacer {
+	return &pacer{
+		clock: clock,
+		rate:  rate,
+		delay: delay,
+		start: clock.Now().Add(delay),
+	}
+}
+
+// playTime returns the play time of the frame of the schedule.
+func (p *pacer) playTime(frame uint64) time.Time {
+	seconds := frame / uint64(p.rate)
+	remainder := frame % uint64(p.rate)
+
+	return p.start.
+		Add(time.Duration(seconds) * time.Second).
+		Add(time.Duration(remainder) * time.Second / time.Duration(p.rate))
+}
+
+// Schedule returns the schedule of the next block of the frames. A block that would be sent after its play time by
+// more than the larger of the late block tolerance and its duration starts a new schedule with the presentation delay,
+// the clients would drop it anyway.
+func (p *pacer) Schedule(frames int) blockSchedule {
+	now := p.clock.Now()
+	schedule := blockSchedule{playAt: p.playTime(p.frames)}
+
+	tolerance := p.rate.D(frames)
+
+	if tolerance < lateBlockTolerance {
+		tolerance = lateBlockTolerance
+	}
+
+	if now.Sub(schedule.playAt) > tolerance {
+		schedule.late = now.Sub(schedule.playAt) + p.delay
+		schedule.restarted = true
+
+		p.start = now.Add(p.delay)
+		p.frames = 0
+		schedule.playAt = p.start
+	} else if sendAt := schedule.playAt.Add(-p.delay); now.After(sendAt) {
+		schedule.late = now.Sub(sendAt)
+	}
+
+	p.frames += uint64(frames)
+
+	return schedule
+}
+
+// End returns the play time of the end of the last scheduled block.
+func (p *pacer) End() time.Time {
+	return p.playTime(p.frames)
+}
+
+// Wait sleeps until the next block is due, it returns immediately while the pacer catches up.
+func (p *pacer) Wait() {
+	wait := p.End().Add(-p.delay).Sub(p.clock.Now())
+
+	if wait > 0 {
+		p.clock.Sleep(wait)
+	}
+}
@@ -0,0 +1,215 @@
+package player
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/faiface/beep"
+	"github.com/sirupsen/logrus"
+
+	"network-audio/pkg/messages"
+	"network-audio/pkg/timex"
+)
+
+// testClock advances only when slept, every sleep takes the oversleep longer than asked for.
+type testClock struct {
+	now       time.Time
+	oversleep time.Duration
+	slept     time.Duration
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) Sleep(d time.Duration) {
+	c.now = c.now.Add(d + c.oversleep)
+	c.slept += d + c.oversleep
+}
+
+func TestPacer(t *testing.T) {
+	rate := beep.SampleRate(44100)
+	delay := time.Millisecond * 500
+
+	t.Run(
+		"should not drift over a long stream",
+		func(t *testing.T) {
+			clock := &testClock{now: time.Unix(1000, 0), oversleep: time.Millisecond}
+			pacer := newPacer(clock, rate, delay)
+			start := clock.now.Add(delay)
+
+			// about an hour in blocks of 512 frames
+			blocks := 310078
+
+			for i := 0; i < blocks; i++ {
+				schedule := pacer.Schedule(512)
+
+				if schedule.restarted {
+					t.Fatalf("block %d restarted the schedule", i)
+				}
+
+				if schedule.late > clock.oversleep {
+					t.Fatalf("block %d is late by %s", i, schedule.late)
+				}
+
+				pacer.Wait()
+			}
+
+			expected := start.Add(time.Duration(blocks*512) * time.Second / time.Duration(rate))
+
+			if !pacer.End().Equal(expected) {
+				t.Errorf("expected the stream to end at %s, got %s", expected, pacer.End())
+			}
+
+			// the clock runs one oversleep behind the schedule at most
+			if lag := clock.now.Sub(pacer.End().Add(-delay)); lag < 0 || lag > clock.oversleep {
+				t.Errorf("expected the clock to follow the schedule, it is off by %s", lag)
+			}
+		},
+	)
+
+	t.Run(
+		"should keep the schedule without a presentation delay",
+		func(t *testing.T) {
+			clock := &testClock{now: time.Unix(1000, 0), oversleep: time.Millisecond}
+			pacer := newPacer(clock, rate, 0)
+			start := clock.now
+
+			blocks := 10000
+
+			for i := 0; i < blocks; i++ {
+				schedule := pacer.Schedule(512)
+
+				if schedule.restarted {
+					t.Fatalf("block %d restarted the schedule", i)
+				}
+
+				if schedule.late > clock.oversleep {
+					t.Fatalf("block %d is late by %s", i, schedule.late)
+				}
+
+				pacer.Wait()
+			}
+
+			expected := start.Add(time.Duration(blocks*512) * time.Second / time.Duration(rate))
+
+			if !pacer.End().Equal(expected) {
+				t.Errorf("expected the stream to end at %s, got %s", expected, pacer.End())
+			}
+		},
+	)
+
+	t.Run(
+		"should catch up after a stall within the presentation delay",
+		func(t *testing.T) {
+			clock := &testClock{now: time.Unix(1000, 0)}
+			pacer := newPacer(clock, rate, delay)
+
+			first := pacer.Schedule(512)
+			pacer.Wait()
+
+			// the source stalls for 200 ms
+			clock.now = clock.now.Add(time.Millisecond * 200)
+
+			previous := first.playAt
+			caughtUp := false
+
+			for i := 1; i < 40; i++ {
+				before := clock.slept
+				schedule := pacer.Schedule(512)
+
+				if schedule.restarted {
+					t.Fatalf("block %d restarted the schedule", i)
+				}
+
+				if i == 1 && schedule.late < time.Millisecond*190 {
+					t.Errorf("expected the block after the stall to be late, got %s", schedule.late)
+				}
+
+				if schedule.playAt.Sub(previous) != rate.D(512) && schedule.playAt.Sub(previous) != rate.D(512)+1 {
+					t.Fatalf("expected block %d to follow the previous one, the gap is %s", i, schedule.playAt.Sub(previous))
+				}
+
+				previous = schedule.playAt
+				pacer.Wait()
+
+				if clock.slept > before {
+					caughtUp = true
+				} else if caughtUp {
+					t.Fatalf("block %d did not wait after catching up", i)
+				}
+			}
+
+			if !caughtUp {
+				t.Error("expected the pacer to catch up")
+			}
+		},
+	)
+
+	t.Run(
+		"should restart the schedule after a stall longer than the presentation delay",
+		func(t *testing.T) {
+			clock := &testClock{now: time.Unix(1000, 0)}
+			pacer := newPacer(clock, rate, delay)
+
+			pacer.Schedule(512)
+			pacer.Wait()
+
+			clock.now = clock.now.Add(time.Second)
+
+			schedule := pacer.Schedule(512)
+
+			if !schedule.restarted {
+				t.Fatal("expected the schedule to restart")
+			}
+
+			if !schedule.playAt.Equal(clock.now.Add(delay)) {
+				t.Errorf("expected the block to play after the delay, got %s", schedule.playAt.Sub(clock.now))
+			}
+		},
+	)
+}
+
+func TestPlayStreamSchedule(t *testing.T) {
+	clock := &testClock{now: time.Unix(1000, 0)}
+	target := &recordingTarget{lock: &sync.Mutex{}}
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	p := New(target, logger, WithClock(clock), WithPresentationDelay(time.Millisecond*300))
+	start := clock.now.Add(time.Millisecond * 300)
+
+	err := p.playStream(beep.Take(44100*3, beep.Silence(-1)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	frames := 0
+
+	for _, msg := range target.messages {
+		m, ok := msg.(*messages.Audio)
+
+		if !ok {
+			continue
+		}
+
+		expected := start.Add(p.format.SampleRate.D(frames))
+
+		if diff := timex.ToTime(m.Time).Sub(expected); diff < -time.Nanosecond || diff > time.Nanosecond {
+			t.Fatalf("expected the block at frame %d to play at %s, got %s", frames, expected, timex.ToTime(m.Time))
+		}
+
+		frames += m.Frames()
+	}
+
+	if frames != 44100*3 {
+		t.Errorf("expected 3 seconds of audio, got %d frames", frames)
+	}
+
+	// the stream was sent ahead of its play time by the presentation delay
+	if lead := start.Add(time.Second * 3).Sub(clock.now); lead < time.Millisecond*290 || lead > time.Millisecond*300 {
+		t.Errorf("expected the stream to be sent 300ms ahead, got %s", lead)
+	}
+}
@@ -2,13 +2,19 @@ package player
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/faiface/beep"
 	"github.com/faiface/beep/mp3"
+	"github.com/faiface/beep/vorbis"
 	"github.com/sirupsen/logrus"
 
+	"network-audio/pkg/dsp"
 	"network-audio/pkg/messages"
+	"network-audio/pkg/metadata"
 	"network-audio/pkg/timex"
 )
 
@@ -20,6 +26,31 @@ type Player struct {
 	streamBufferSize int
 	resampleQuality  int
 
+	// Duration of the equal-power crossfade between two tracks, tracks are played gapless without a crossfade
+	crossfade time.Duration
+
+	// Target loudness in LUFS, tracks are normalized if a cache is set
+	loudnessTarget float64
+	loudnessCache  *loudnessCache
+
+	// Input buffer and stall timeout of HTTP streams
+	httpBufferSize int
+	stallTimeout   time.Duration
+
+	// Interval in which the playback state is published
+	stateInterval time.Duration
+
+	// Time between sending a block and its play time, it leaves the clients room for the network delay
+	presentationDelay time.Duration
+
+	clock Clock
+
+	// Blocks sent later than their send time, they eat into the presentation delay
+	lateBlocks uint64
+
+	nowPlaying *messages.NowPlaying
+	lock       *sync.RWMutex
+
 	stopChan chan bool
 }
 
@@ -37,14 +68,56 @@ func WithResampleQuality(resampleQuality int) Option {
 	}
 }
 
+func WithCrossfade(crossfade time.Duration) Option {
+	return func(p *Player) {
+		p.crossfade = crossfade
+	}
+}
+
+// WithLoudnessNormalization normalizes every track to the target loudness in LUFS and limits the true peak.
+// The analysis of the tracks is stored in the cache file, an empty path keeps it in memory only.
+func WithLoudnessNormalization(target float64, cachePath string) Option {
+	return func(p *Player) {
+		p.loudnessTarget = target
+		p.loudnessCache = newLoudnessCache(cachePath)
+	}
+}
+
+// WithHTTPBuffer sets the input buffer size of HTTP streams in bytes and the time without data after which they reconnect.
+func WithHTTPBuffer(size int, stallTimeout time.Duration) Option {
+	return func(p *Player) {
+		p.httpBufferSize = size
+		p.stallTimeout = stallTimeout
+	}
+}
+
+func WithStateInterval(stateInterval time.Duration) Option {
+	return func(p *Player) {
+		p.stateInterval = stateInterval
+	}
+}
+
+// WithPresentationDelay stamps the blocks to be played the delay after they are sent, the stream is sent ahead by it.
+func WithPresentationDelay(presentationDelay time.Duration) Option {
+	return func(p *Player) {
+		p.presentationDelay = presentationDelay
+	}
+}
+
 func New(target Target, logger logrus.FieldLogger, options ...Option) *Player {
 	p := &Player{
-		logger:           logger,
-		format:           beep.Format{SampleRate: 44100, Precision: 2, NumChannels: 2},
-		resampleQuality:  3,
-		streamBufferSize: 512,
-		target:           target,
-		stopChan:         make(chan bool),
+		logger:            logger,
+		format:            beep.Format{SampleRate: 44100, Precision: 2, NumChannels: 2},
+		resampleQuality:   3,
+		stateInterval:     time.Second,
+		presentationDelay: time.Millisecond * 500,
+		clock:             systemClock{},
+		httpBufferSize:    256 * 1024,
+		stallTimeout:      time.Second * 10,
+		streamBufferSize:  512,
+		target:            target,
+		lock:              &sync.RWMutex{},
+		stopChan:          make(chan bool, 1),
 	}
 
 	for _, option := range options {
@@ -52,45 +125,160 @@ func New(target Target, logger logrus.FieldLogger, options ...Option) *Player {
 	}
 
 	p.logger.Infof("Player: streamBufferSize: %d", p.streamBufferSize)
+	p.logger.Infof("Player: presentationDelay: %s", p.presentationDelay)
 
 	return p
 }
 
+// BlockSize returns the frames of the audio messages.
+func (p *Player) BlockSize() int {
+	return p.streamBufferSize
+}
+
+func (p *Player) Format() beep.Format {
+	return p.format
+}
+
 // PlayFile is a blocking function that plays a file.
 func (p *Player) PlayFile(filePath string) error {
-	p.stopChan = make(chan bool)
+	return p.PlayFiles([]string{filePath}, false)
+}
+
+// PlayFiles is a blocking function that plays the files one after another without gaps,
+// with loop enabled it starts over after the last file until the player is stopped.
+func (p *Player) PlayFiles(filePaths []string, loop bool) error {
+	p.stopChan = make(chan bool, 1)
+
+	pl := newPlaylist(p, filePaths, loop)
+	defer pl.Close()
+
+	if p.loudnessCache != nil {
+		// the first track is measured before it plays, the playlist analyzes every next track while the one before plays
+		if len(filePaths) > 0 {
+			p.analyzeFile(filePaths[0])
+		}
+
+		p.analyze(filePaths)
+		pl.limiter = dsp.NewLimiter(p.format.SampleRate, truePeakCeiling)
+	}
+
+	return p.playStream(pl)
+}
+
+// PresentationDelay returns the time between sending a block and its play time.
+func (p *Player) PresentationDelay() time.Duration {
+	return p.presentationDelay
+}
+
+// NowPlaying returns the announcement of the current track or nil if nothing has been played yet.
+func (p *Player) NowPlaying() *messages.NowPlaying {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
 
-	stream, err := p.getFileStream(filePath)
+	return p.nowPlaying
+}
+
+// startTrack announces the track to the target, it starts playing after the presentation delay.
+func (p *Player) startTrack(t *track) {
+	t.started = true
+
+	if t.awaitTitle && t.metadata == nil {
+		return
+	}
+
+	msg := t.nowPlaying(p.format, p.clock.Now().Add(p.presentationDelay))
+
+	p.lock.Lock()
+	p.nowPlaying = msg
+	p.lock.Unlock()
+
+	err := p.target.Send(msg)
+
+	if err != nil {
+		p.logger.Errorf("send now playing error: %s", err)
+	}
+}
+
+func (p *Player) openTrack(filePath string) (*track, error) {
+	if isURL(filePath) {
+		return p.openHTTPTrack(filePath)
+	}
+
+	source, format, err := p.getFileStream(filePath)
 
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	t := newTrack(filePath, source, format, p)
+
+	t.metadata, err = metadata.Read(filePath)
+
+	if err != nil {
+		p.logger.Warnf("read metadata of %s error: %s", filePath, err)
+	}
+
+	if p.loudnessCache != nil {
+		t.gain = p.normalizationGain(filePath, t.metadata)
 	}
 
-	return p.playStream(stream)
+	return t, nil
 }
 
-func (p *Player) getFileStream(filePath string) (beep.Streamer, error) {
+func (p *Player) getFileStream(filePath string) (beep.StreamSeekCloser, beep.Format, error) {
 	file, err := os.Open(filePath)
 
 	if err != nil {
-		return nil, err
+		return nil, beep.Format{}, err
 	}
 
-	stream, format, err := mp3.Decode(file)
+	var stream beep.StreamSeekCloser
+	var format beep.Format
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".ogg", ".oga":
+		stream, format, err = vorbis.Decode(file)
+	default:
+		stream, format, err = mp3.Decode(file)
+	}
 
 	if err != nil {
-		return nil, err
+		_ = file.Close()
+		return nil, beep.Format{}, err
+	}
+
+	return stream, format, nil
+}
+
+func (p *Player) crossfadeSamples() int {
+	return p.format.SampleRate.N(p.crossfade)
+}
+
+// reportLateBlock warns about blocks that were sent later than the tolerance, they leave the clients less time.
+func (p *Player) reportLateBlock(schedule blockSchedule) {
+	if schedule.restarted {
+		p.logger.Warnf("stream is behind by %s, restarting it with the presentation delay", schedule.late)
+
+		return
 	}
 
-	if p.format.SampleRate != format.SampleRate {
-		return beep.Resample(p.resampleQuality, format.SampleRate, p.format.SampleRate, stream), nil
+	if schedule.late <= lateBlockTolerance {
+		return
 	}
 
-	return stream, nil
+	p.lateBlocks++
+
+	if p.lateBlocks%100 == 1 {
+		p.logger.Warnf("block sent %s late, %d late blocks", schedule.late, p.lateBlocks)
+	}
 }
 
+// Stop stops the playback, it does not block if nothing is playing.
 func (p *Player) Stop() {
-	p.stopChan <- true
+	select {
+	case p.stopChan <- true:
+	default:
+	}
 }
 
 func (p *Player) playStream(stream beep.Streamer) error {
@@ -98,14 +286,18 @@ func (p *Player) playStream(stream beep.Streamer) error {
 	ok := true
 	samplesAmount := p.streamBufferSize
 
+	// frames sent since the start of the stream
+	frames := uint64(0)
+	lastState := time.Time{}
+
+	pacer := newPacer(p.clock, p.format.SampleRate, p.presentationDelay)
+
 LOOP:
 	for {
 		select {
 		case _ = <-p.stopChan:
 			ok = false
 		default:
-			iterationStart := time.Now()
-
 			if ok != true {
 				break LOOP
 			}
@@ -120,23 +312,31 @@ LOOP:
 				samplesRight[i] = buffer[i][1]
 			}
 
-			playbackInterval := p.format.SampleRate.D(samplesAmount)
+			schedule := pacer.Schedule(samplesAmount)
+			p.reportLateBlock(schedule)
 
-			err := p.target.Send(
-				&messages.Audio{
-					Left:  samplesLeft,
-					Right: samplesRight,
-					Time:  timex.ToTimestamp(time.Now().Add(time.Nanosecond * 100)),
-				},
-			)
+			msg := messages.NewAudio(messages.ChannelLayout_CHANNEL_LAYOUT_STEREO, samplesLeft, samplesRight)
+			msg.Time = timex.ToTimestamp(schedule.playAt)
+			msg.SampleRate = uint32(p.format.SampleRate)
+
+			err := p.target.Send(msg)
 
 			if err != nil {
 				return err
 			}
 
-			time.Sleep(playbackInterval - time.Since(iterationStart) - time.Nanosecond*100)
+			frames += uint64(samplesAmount)
+
+			if now := p.clock.Now(); now.Sub(lastState) >= p.stateInterval {
+				lastState = now
+				p.sendPlaybackState(stream, messages.PlaybackStatus_PLAYBACK_STATUS_PLAYING, frames, pacer.End())
+			}
+
+			pacer.Wait()
 		}
 	}
 
+	p.sendPlaybackState(stream, messages.PlaybackStatus_PLAYBACK_STATUS_STOPPED, frames, pacer.End())
+
 	return nil
 }
@@ -0,0 +1,186 @@
+package player
+
+import (
+	"math"
+
+	"network-audio/pkg/dsp"
+)
+
+// playlist streams tracks one after another without gaps. With a crossfade, the next track is opened before the
+// current one ends and both are mixed with equal-power gains.
+type playlist struct {
+	player *Player
+	paths  []string
+	loop   bool
+	index  int
+
+	current *track
+	next    *track
+
+	// length of the running crossfade in samples
+	fadeLength int
+	buffer     [][2]float64
+
+	// keeps the true peak of the normalized tracks below the ceiling
+	limiter dsp.Processor
+}
+
+func newPlaylist(player *Player, paths []string, loop bool) *playlist {
+	return &playlist{
+		player: player,
+		paths:  paths,
+		loop:   loop,
+	}
+}
+
+// open opens the next track of the playlist, files that fail to open are skipped.
+func (pl *playlist) open() *track {
+	for failed := 0; failed < len(pl.paths); failed++ {
+		if pl.index >= len(pl.paths) {
+			if !pl.loop {
+				return nil
+			}
+
+			pl.index = 0
+		}
+
+		path := pl.paths[pl.index]
+		pl.index++
+
+		t, err := pl.player.openTrack(path)
+
+		if err != nil {
+			pl.player.logger.Errorf("open file %s error: %s", path, err)
+			continue
+		}
+
+		pl.player.logger.Infof("start to play %s", path)
+		pl.player.startTrack(t)
+
+		if upcoming := pl.upcoming(); upcoming != "" {
+			pl.player.analyze([]string{upcoming})
+		}
+
+		return t
+	}
+
+	return nil
+}
+
+// upcoming returns the path of the entry after the current track or an empty string at the end of the playlist.
+func (pl *playlist) upcoming() string {
+	if pl.index < len(pl.paths) {
+		return pl.paths[pl.index]
+	}
+
+	if pl.loop && len(pl.paths) > 0 {
+		return pl.paths[0]
+	}
+
+	return ""
+}
+
+func (pl *playlist) Stream(samples [][2]float64) (n int, ok bool) {
+	crossfade := pl.player.crossfadeSamples()
+
+	for n < len(samples) {
+		if pl.current == nil {
+			pl.current = pl.open()
+
+			if pl.current == nil {
+				break
+			}
+		}
+
+		chunk := samples[n:]
+		remaining := pl.current.remaining()
+
+		if crossfade > 0 && pl.next == nil {
+			if remaining <= crossfade {
+				pl.next = pl.open()
+				pl.fadeLength = remaining
+			} else if len(chunk) > remaining-crossfade {
+				// stop at the start of the crossfade
+				chunk = chunk[:remaining-crossfade]
+			}
+		}
+
+		read, _ := pl.current.Stream(chunk)
+
+		if pl.next != nil && read > 0 {
+			pl.mix(chunk[:read], remaining)
+		}
+
+		n += read
+
+		if read < len(chunk) {
+			pl.advance()
+		}
+	}
+
+	if pl.limiter != nil {
+		pl.limiter.Process(samples[:n])
+	}
+
+	return n, n > 0
+}
+
+// mix fades the current track out and the next track in.
+func (pl *playlist) mix(samples [][2]float64, remaining int) {
+	if cap(pl.buffer) < len(samples) {
+		pl.buffer = make([][2]float64, len(samples))
+	}
+
+	buffer := pl.buffer[:len(samples)]
+	read, _ := pl.next.Stream(buffer)
+
+	for i := read; i < len(buffer); i++ {
+		buffer[i] = [2]float64{}
+	}
+
+	for i := range samples {
+		progress := 1.0
+
+		if pl.fadeLength > 0 {
+			progress = float64(pl.fadeLength-remaining+i) / float64(pl.fadeLength)
+		}
+
+		progress = math.Max(0, math.Min(1, progress))
+
+		out := math.Cos(progress * math.Pi / 2)
+		in := math.Sin(progress * math.Pi / 2)
+
+		samples[i][0] = samples[i][0]*out + buffer[i][0]*in
+		samples[i][1] = samples[i][1]*out + buffer[i][1]*in
+	}
+}
+
+// advance closes the ended track and continues with the next one.
+func (pl *playlist) advance() {
+	if err := pl.current.Err(); err != nil {
+		pl.player.logger.Errorf("play file %s error: %s", pl.current.path, err)
+	}
+
+	_ = pl.current.Close()
+
+	pl.current = pl.next
+	pl.next = nil
+	pl.fadeLength = 0
+}
+
+func (pl *playlist) Err() error {
+	return nil
+}
+
+func (pl *playlist) Close() {
+	if pl.current != nil {
+		_ = pl.current.Close()
+	}
+
+	if pl.next != nil {
+		_ = pl.next.Close()
+	}
+
+	pl.current = nil
+	pl.next = nil
+}
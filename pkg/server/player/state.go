@@ -0,0 +1,54 @@
+package player
+
+import (
+	"time"
+
+	"github.com/faiface/beep"
+
+	"network-audio/pkg/messages"
+	"network-audio/pkg/timex"
+)
+
+// positioner is implemented by streams that know the track they are playing.
+type positioner interface {
+	// position returns the current track and the amount of its samples streamed so far.
+	position() (*track, int)
+}
+
+func (pl *playlist) position() (*track, int) {
+	if pl.current == nil {
+		return nil, 0
+	}
+
+	return pl.current, pl.current.position
+}
+
+// playbackState describes the state of the stream after frames have been streamed,
+// the last streamed frame is played at the given time.
+func (p *Player) playbackState(stream beep.Streamer, status messages.PlaybackStatus, frames uint64, at time.Time) *messages.PlaybackState {
+	msg := &messages.PlaybackState{
+		State:    status,
+		Position: p.format.SampleRate.D(int(frames)).Nanoseconds(),
+		Time:     timex.ToTimestamp(at),
+		Frames:   frames,
+	}
+
+	if s, ok := stream.(positioner); ok {
+		if t, position := s.position(); t != nil {
+			msg.TrackId = t.id
+			msg.Position = p.format.SampleRate.D(position).Nanoseconds()
+			msg.Duration = t.duration(p.format).Nanoseconds()
+		}
+	}
+
+	return msg
+}
+
+// sendPlaybackState publishes the playback state to the target.
+func (p *Player) sendPlaybackState(stream beep.Streamer, status messages.PlaybackStatus, frames uint64, at time.Time) {
+	err := p.target.Send(p.playbackState(stream, status, frames, at))
+
+	if err != nil {
+		p.logger.Errorf("send playback state error: %s", err)
+	}
+}
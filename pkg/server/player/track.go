@@ -0,0 +1,115 @@
+package player
+
+import (
+	"math"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/faiface/beep"
+
+	"network-audio/pkg/dsp"
+	"network-audio/pkg/messages"
+	"network-audio/pkg/metadata"
+	"network-audio/pkg/timex"
+	"network-audio/pkg/ulidx"
+)
+
+// track is a decoded file converted to the format of the player.
+type track struct {
+	id       string
+	path     string
+	metadata *metadata.Metadata
+	streamer beep.Streamer
+	source   beep.StreamCloser
+
+	// length and position in samples of the player format, the length of live streams is -1
+	length   int
+	position int
+
+	// linear gain of the loudness normalization
+	gain float64
+
+	// streams with titles are announced with their first title instead of their URL
+	awaitTitle bool
+	started    bool
+}
+
+func newTrack(path string, source beep.StreamSeekCloser, format beep.Format, p *Player) *track {
+	t := &track{
+		id:       ulidx.MustNew().String(),
+		path:     path,
+		streamer: source,
+		source:   source,
+		length:   source.Len(),
+		gain:     1,
+	}
+
+	if p.format.SampleRate != format.SampleRate {
+		t.streamer = beep.Resample(p.resampleQuality, format.SampleRate, p.format.SampleRate, source)
+		t.length = int(int64(t.length) * int64(p.format.SampleRate) / int64(format.SampleRate))
+	}
+
+	return t
+}
+
+// duration returns the length of the track in the format of the player.
+func (t *track) duration(format beep.Format) time.Duration {
+	if t.length < 0 {
+		return 0
+	}
+
+	return format.SampleRate.D(t.length)
+}
+
+// nowPlaying creates the message announcing the track, starting at the given time.
+func (t *track) nowPlaying(format beep.Format, start time.Time) *messages.NowPlaying {
+	msg := &messages.NowPlaying{
+		TrackId:  t.id,
+		Title:    strings.TrimSuffix(filepath.Base(t.path), filepath.Ext(t.path)),
+		Duration: t.duration(format).Nanoseconds(),
+		Time:     timex.ToTimestamp(start),
+	}
+
+	if t.metadata != nil {
+		msg.Title = t.metadata.Title
+		msg.Artist = t.metadata.Artist
+		msg.Album = t.metadata.Album
+		msg.Artwork = t.metadata.Artwork
+		msg.ArtworkType = t.metadata.ArtworkType
+	}
+
+	return msg
+}
+
+func (t *track) Stream(samples [][2]float64) (n int, ok bool) {
+	n, ok = t.streamer.Stream(samples)
+	t.position += n
+
+	if t.gain != 1 {
+		dsp.Gain(t.gain).Process(samples[:n])
+	}
+
+	return n, ok
+}
+
+func (t *track) Err() error {
+	return t.streamer.Err()
+}
+
+// remaining returns the amount of samples left in the track.
+func (t *track) remaining() int {
+	if t.length < 0 {
+		return math.MaxInt
+	}
+
+	if t.position > t.length {
+		return 0
+	}
+
+	return t.length - t.position
+}
+
+func (t *track) Close() error {
+	return t.source.Close()
+}
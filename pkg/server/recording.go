@@ -0,0 +1,45 @@
+package server
+
+import (
+	"network-audio/pkg/audio"
+	"network-audio/pkg/capture"
+	"network-audio/pkg/messages"
+	"network-audio/pkg/timex"
+)
+
+// WithRecording records the broadcast audio with the time it is scheduled for, the caller closes the capture.
+func WithRecording(recording *capture.Capture) Option {
+	return func(s *Server) {
+		s.recording = recording
+	}
+}
+
+func (s *Server) record(m *messages.Audio) {
+	if m.SampleRate != 0 && int(m.SampleRate) != int(s.recording.Format().SampleRate) {
+		s.logger.Warnf("not recording audio with sample rate %d, the recording has %d", m.SampleRate, s.recording.Format().SampleRate)
+		return
+	}
+
+	err := s.recording.Write(stereoSamples(m), timex.ToTime(m.Time))
+
+	if err != nil {
+		s.logger.Errorf("recording error: %s\n", err)
+	}
+}
+
+// stereoSamples downmixes the channels of the audio message to stereo frames.
+func stereoSamples(m *messages.Audio) [][2]float64 {
+	layout := audio.Layout(m.Layout)
+	samples := make([][2]float64, m.Frames())
+	data := make([]float64, len(m.Channels))
+
+	for index := range samples {
+		for channel, c := range m.Channels {
+			data[channel] = c.Samples[index]
+		}
+
+		samples[index] = audio.StereoMap(data, layout)
+	}
+
+	return samples
+}
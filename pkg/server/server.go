@@ -2,12 +2,15 @@ package server
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/panjf2000/gnet/v2"
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/protobuf/proto"
 
+	"network-audio/pkg/capture"
 	"network-audio/pkg/logx"
 	"network-audio/pkg/messages"
 	"network-audio/pkg/server/player"
@@ -23,27 +26,119 @@ type Server struct {
 	address string
 	logger  logrus.FieldLogger
 
-	player *player.Player
+	player        *player.Player
+	playerOptions []player.Option
+	files         []string
 
 	clients  *sync.Map
 	stopChan chan bool
 
+	// limits of the frames queued per client, see outbound
+	outboundDroppable  int
+	outboundGuaranteed int
+	slowClientTimeout  time.Duration
+
+	// serves the broadcast to http listeners if an address is set
+	httpOutputAddress string
+	httpOutput        *httpOutput
+
+	// serves the protocol to browsers if an address is set
+	webSocketAddress string
+	webSocket        *webSocketEndpoint
+
+	// the audio sent last, new clients receive its pending part
+	historyDuration time.Duration
+	history         *history
+
+	// records the broadcast if set
+	recording *capture.Capture
+
+	// topics of the clients, keyed by their address
+	subscriptions *sync.Map
+
+	// the broadcast waits while a client opens, so the client receives the history before newer audio and each block
+	// only once
+	lock *sync.RWMutex
+
+	// output latency offsets and dsp configs of the clients, keyed by their host
+	outputLatencies *sync.Map
+	dspConfigs      *sync.Map
+
+	// the host of the client that records the calibration signal
+	calibrationReference string
+	calibrationResults   chan *messages.CalibrationResult
+	calibrating          int32
+
 	counter uint64
 }
 
-func New(logger logrus.FieldLogger, address string) *Server {
+type Option func(*Server)
+
+// WithPlayerOptions passes the options to the player of the server, e.g. to stream in a different format.
+func WithPlayerOptions(options ...player.Option) Option {
+	return func(s *Server) {
+		s.playerOptions = append(s.playerOptions, options...)
+	}
+}
+
+// WithFiles sets the files that are played in a loop.
+func WithFiles(files ...string) Option {
+	return func(s *Server) {
+		s.files = files
+	}
+}
+
+func New(logger logrus.FieldLogger, address string, options ...Option) *Server {
 	s := &Server{
-		logger:   logger,
-		address:  address,
-		clients:  &sync.Map{},
-		stopChan: make(chan bool),
+		logger:          logger,
+		address:         address,
+		clients:         &sync.Map{},
+		subscriptions:   &sync.Map{},
+		lock:            &sync.RWMutex{},
+		stopChan:        make(chan bool),
+		files:           []string{"./test/audio.mp3"},
+		outputLatencies: &sync.Map{},
+		dspConfigs:      &sync.Map{},
+
+		slowClientTimeout: time.Second * 5,
+		historyDuration:   time.Second * 2,
+
+		calibrationResults: make(chan *messages.CalibrationResult, 1),
+	}
+
+	for _, option := range options {
+		option(s)
 	}
 
 	s.player = player.New(
 		s,
 		logx.Component(logger, "player"),
+		s.playerOptions...,
 	)
 
+	if s.httpOutputAddress != "" {
+		s.httpOutput = newHTTPOutput(logx.Component(logger, "http-output"), s.httpOutputAddress, s.player.Format())
+	}
+
+	if s.historyDuration > 0 {
+		s.history = newHistory(s.historyDuration, s.player.Format().SampleRate)
+	}
+
+	// the queues fit the history burst at the sample rate of the broadcast unless they are set
+	droppable, guaranteed := outboundLimits(s.player.Format().SampleRate, s.player.BlockSize(), s.historyDuration)
+
+	if s.outboundDroppable == 0 {
+		s.outboundDroppable = droppable
+	}
+
+	if s.outboundGuaranteed == 0 {
+		s.outboundGuaranteed = guaranteed
+	}
+
+	if s.webSocketAddress != "" {
+		s.webSocket = newWebSocketEndpoint(logx.Component(logger, "websocket"), s.webSocketAddress, s)
+	}
+
 	return s
 }
 
@@ -52,36 +147,25 @@ func (s *Server) OnBoot(engine gnet.Engine) gnet.Action {
 
 	s.logger.Infof("server is listening on %s\n", s.address)
 
-	// loop the file player
-	go func() {
-		for {
-			select {
-			case <-s.stopChan:
-				return
-			default:
-				wg := &sync.WaitGroup{}
-
-				wg.Add(1)
-
-				go func() {
-					s.logger.Info("start to play file")
-					defer s.player.Stop()
-					err := s.player.PlayFile("./test/audio.mp3")
-
-					if err != nil {
-						s.logger.Errorf("play file error: %s\n", err)
-					}
+	if s.httpOutput != nil {
+		s.httpOutput.Start()
+	}
 
-					wg.Done()
-				}()
+	if s.webSocket != nil {
+		s.webSocket.Start()
+	}
 
-				wg.Wait()
+	// loop the files without gaps until the server is stopped
+	go func() {
+		s.logger.Infof("start to play %d files", len(s.files))
 
-				s.logger.Info("file play done")
+		err := s.player.PlayFiles(s.files, true)
 
-				time.Sleep(time.Second)
-			}
+		if err != nil {
+			s.logger.Errorf("play files error: %s\n", err)
 		}
+
+		s.logger.Info("file play done")
 	}()
 
 	return gnet.None
@@ -90,34 +174,60 @@ func (s *Server) OnBoot(engine gnet.Engine) gnet.Action {
 func (s *Server) OnShutdown(engine gnet.Engine) {
 	s.player.Stop()
 
+	if s.httpOutput != nil {
+		s.httpOutput.Close()
+	}
+
+	if s.webSocket != nil {
+		s.webSocket.Close()
+	}
+
 	s.logger.Info("server is shutdown")
 }
 
+// OnTraffic handles all complete frames, a partial frame stays buffered until the rest arrives.
 func (s *Server) OnTraffic(c gnet.Conn) gnet.Action {
-	msg, err := messages.FromConnection(c)
+	for {
+		msg, err := messages.FromConnection(c)
 
-	if err != nil {
-		s.logger.Errorf("read message error: %s\n", err)
+		if errors.Is(err, messages.ErrIncompleteFrame) {
+			return gnet.None
+		}
 
-		return gnet.None
-	}
+		// corrupt or unknown frames are skipped, the connection stays in sync
+		if err != nil {
+			s.logger.Errorf("read message error: %s\n", err)
 
-	switch m := msg.(type) {
-	case *messages.Time:
-		sent := timex.ToTime(m.Time)
-		received := time.Now()
-		latency := received.Sub(sent)
+			continue
+		}
 
-		msg := &messages.Latency{
-			Latency: latency.Nanoseconds(),
-			Time:    timex.ToTimestamp(time.Now()),
+		if action := s.handle(c, msg); action != gnet.None {
+			return action
 		}
+	}
+}
 
-		err := s.SendTo(c, msg)
+func (s *Server) handle(c gnet.Conn, msg proto.Message) gnet.Action {
+	switch m := msg.(type) {
+	case *messages.Time:
+		err := s.SendTo(c, latencyOf(m))
 
 		if err != nil {
 			return gnet.Close
 		}
+	case *messages.Subscription:
+		s.logger.Infof("%s subscribed to %v", c.RemoteAddr().String(), m.Topics)
+		s.Subscribe(c, m.Topics)
+	case *messages.OutputLatency:
+		if err := s.setOutputLatencyOf(remoteHost(c), m); err != nil {
+			s.logger.Errorf("set output latency error: %s\n", err)
+		}
+	case *messages.CalibrationResult:
+		select {
+		case s.calibrationResults <- m:
+		default:
+			s.logger.Warnf("dropping unexpected calibration result for %s", m.Target)
+		}
 	default:
 		s.logger.Errorf("unknown message type: %T\n", m)
 		return gnet.None
@@ -126,12 +236,58 @@ func (s *Server) OnTraffic(c gnet.Conn) gnet.Action {
 	return gnet.None
 }
 
+// latencyOf answers the time message of a client with the time it took to arrive and the current time.
+func latencyOf(m *messages.Time) *messages.Latency {
+	sent := timex.ToTime(m.Time)
+	received := time.Now()
+	latency := received.Sub(sent)
+
+	return &messages.Latency{
+		Latency: latency.Nanoseconds(),
+		Time:    timex.ToTimestamp(time.Now()),
+	}
+}
+
 func (s *Server) OnOpen(connection gnet.Conn) ([]byte, gnet.Action) {
 	remoteAddr := connection.RemoteAddr().String()
 
 	s.logger.Infof("connection opened: %s\n", remoteAddr)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	connection.SetContext(newOutbound(s.logger, connection, s.outboundDroppable, s.outboundGuaranteed, s.slowClientTimeout))
 	s.clients.Store(remoteAddr, connection)
 
+	err := s.sendHostConfig(connection)
+
+	if err != nil {
+		return nil, gnet.Close
+	}
+
+	if nowPlaying := s.player.NowPlaying(); nowPlaying != nil {
+		err = s.SendTo(connection, nowPlaying)
+
+		if err != nil {
+			return nil, gnet.Close
+		}
+	}
+
+	s.sendHistory(connection)
+
+	if s.calibrationReference != "" && remoteHost(connection) == s.calibrationReference {
+		go func() {
+			// give the clients time to connect and synchronize their clocks
+			time.Sleep(calibrationDelay)
+
+			err := s.Calibrate(s.calibrationReference)
+
+			if err != nil {
+				s.logger.Errorf("calibration error: %s\n", err)
+			}
+		}()
+	}
+
 	return nil, gnet.None
 }
 
@@ -139,7 +295,13 @@ func (s *Server) OnClose(connection gnet.Conn, err error) (action gnet.Action) {
 	remoteAddr := connection.RemoteAddr().String()
 
 	s.logger.Infof("connection closed: %s\n", remoteAddr)
+
+	if o := outboundOf(connection); o != nil {
+		o.Close()
+	}
+
 	s.clients.Delete(remoteAddr)
+	s.subscriptions.Delete(remoteAddr)
 
 	return gnet.None
 }
@@ -158,6 +320,19 @@ func (s *Server) Close() {
 }
 
 func (s *Server) Send(msg proto.Message) error {
+	// the broadcast is muted while a calibration is running
+	if _, ok := msg.(*messages.Audio); ok && atomic.LoadInt32(&s.calibrating) == 1 {
+		return nil
+	}
+
+	if s.httpOutput != nil {
+		s.httpOutput.Send(msg)
+	}
+
+	if m, ok := msg.(*messages.Audio); ok && s.recording != nil {
+		s.record(m)
+	}
+
 	packet := messages.ToPacket(msg)
 	bytes, err := packet.Bytes()
 
@@ -165,7 +340,24 @@ func (s *Server) Send(msg proto.Message) error {
 		return err
 	}
 
-	s.Broadcast(bytes)
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	if m, ok := msg.(*messages.Audio); ok && s.history != nil {
+		s.history.Add(m, bytes)
+	}
+
+	if s.webSocket != nil {
+		s.webSocket.Send(msg, bytes)
+	}
+
+	if topic, ok := topicOf(msg); ok {
+		s.Publish(topic, bytes)
+
+		return nil
+	}
+
+	s.Broadcast(bytes, deliveryOf(msg))
 
 	return nil
 }
@@ -179,27 +371,43 @@ func (s *Server) SendTo(connection gnet.Conn, msg proto.Message) error {
 		return err
 	}
 
-	_, err = connection.Write(bytes)
+	return s.queue(connection, bytes, deliveryOf(msg))
+}
 
-	if err != nil {
-		s.logger.Errorf("error writing to connection: %s\n", err)
-		return err
+// queue adds the bytes to the outbound queue of the connection.
+func (s *Server) queue(connection gnet.Conn, bytes []byte, delivery Delivery) error {
+	o := outboundOf(connection)
+
+	if o == nil {
+		return errors.Errorf("connection %s is not open", connection.RemoteAddr())
 	}
 
-	return nil
+	return o.queue(bytes, delivery)
 }
 
-func (s *Server) Broadcast(bytes []byte) {
-	s.clients.Range(
-		func(key, value interface{}) bool {
+// sendHistory queues the audio that was sent before the connection opened and has not been played yet, the lock is
+// held so no newer audio is broadcast meanwhile.
+func (s *Server) sendHistory(connection gnet.Conn) {
+	if s.history == nil {
+		return
+	}
 
-			go func(connection gnet.Conn) {
-				_, err := connection.Write(bytes)
+	frames := s.history.Pending(time.Now())
 
-				if err != nil {
-					s.logger.Errorf("error writing to connection: %s\n", err)
-				}
-			}(value.(gnet.Conn))
+	for _, frame := range frames {
+		if s.queue(connection, frame, Droppable) != nil {
+			return
+		}
+	}
+
+	s.logger.Infof("sent %d frames of history to %s", len(frames), connection.RemoteAddr())
+}
+
+// Broadcast queues the bytes for all connections, clients that are behind are disconnected.
+func (s *Server) Broadcast(bytes []byte, delivery Delivery) {
+	s.clients.Range(
+		func(key, value interface{}) bool {
+			_ = s.queue(value.(gnet.Conn), bytes, delivery)
 
 			return true
 		},
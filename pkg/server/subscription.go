@@ -0,0 +1,62 @@
+package server
+
+import (
+	"github.com/panjf2000/gnet/v2"
+	"google.golang.org/protobuf/proto"
+
+	"network-audio/pkg/messages"
+)
+
+// topicOf returns the topic of messages that are only sent to subscribed connections.
+func topicOf(msg proto.Message) (messages.Topic, bool) {
+	switch msg.(type) {
+	case *messages.PlaybackState:
+		return messages.Topic_TOPIC_PLAYBACK_STATE, true
+	default:
+		return messages.Topic_TOPIC_UNSPECIFIED, false
+	}
+}
+
+// Subscribe replaces the topics the connection is subscribed to.
+func (s *Server) Subscribe(connection gnet.Conn, topics []messages.Topic) {
+	s.subscribe(connection.RemoteAddr().String(), topics)
+}
+
+// Subscribed returns whether the connection is subscribed to the topic.
+func (s *Server) Subscribed(connection gnet.Conn, topic messages.Topic) bool {
+	return s.subscribed(connection.RemoteAddr().String(), topic)
+}
+
+// subscribe replaces the topics of a client, TCP clients are keyed by their address and web clients by their id.
+func (s *Server) subscribe(key string, topics []messages.Topic) {
+	set := map[messages.Topic]bool{}
+
+	for _, topic := range topics {
+		set[topic] = true
+	}
+
+	s.subscriptions.Store(key, set)
+}
+
+func (s *Server) subscribed(key string, topic messages.Topic) bool {
+	set, ok := s.subscriptions.Load(key)
+
+	return ok && set.(map[messages.Topic]bool)[topic]
+}
+
+// Publish queues the bytes for all connections subscribed to the topic, their delivery is guaranteed.
+func (s *Server) Publish(topic messages.Topic, bytes []byte) {
+	s.clients.Range(
+		func(key, value interface{}) bool {
+			connection := value.(gnet.Conn)
+
+			if !s.Subscribed(connection, topic) {
+				return true
+			}
+
+			_ = s.queue(connection, bytes, Guaranteed)
+
+			return true
+		},
+	)
+}
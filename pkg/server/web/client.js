@@ -0,0 +1,581 @@
+// Browser client of network-audio, it speaks the framed protocol of pkg/messages over a WebSocket
+// and plays the audio with WebAudio at the play time of the server clock.
+"use strict";
+
+// Frame header, see pkg/messages/frame.go
+const MAGIC = [0x4e, 0x41, 0x55, 0x44]; // "NAUD"
+const VERSION = 2;
+const HEADER_SIZE = 20;
+const ENVELOPE_TYPE = 0x01;
+
+// Fields of the Envelope, see pkg/messages/envelope.proto
+const ENVELOPE_AUDIO = 1;
+const ENVELOPE_TIME = 2;
+const ENVELOPE_LATENCY = 3;
+const ENVELOPE_OUTPUT_LATENCY = 5;
+const ENVELOPE_NOW_PLAYING = 8;
+
+// Interval of the time messages, like the Go client
+const TIME_INTERVAL = 100;
+// Clock samples the offset is estimated from, the one with the shortest round trip wins
+const CLOCK_SAMPLES = 32;
+// Blocks closer than this to the end of the previous block are played continuously, in seconds
+const DELAY_THRESHOLD = 0.01;
+// Blocks that would start sooner than this are dropped, in seconds
+const SCHEDULE_MARGIN = 0.005;
+
+const CRC_TABLE = (() => {
+  const table = new Uint32Array(256);
+
+  for (let n = 0; n < 256; n++) {
+    let c = n;
+
+    for (let k = 0; k < 8; k++) {
+      c = c & 1 ? 0xedb88320 ^ (c >>> 1) : c >>> 1;
+    }
+
+    table[n] = c >>> 0;
+  }
+
+  return table;
+})();
+
+function crc32(crc, bytes) {
+  crc = ~crc;
+
+  for (let i = 0; i < bytes.length; i++) {
+    crc = CRC_TABLE[(crc ^ bytes[i]) & 0xff] ^ (crc >>> 8);
+  }
+
+  return ~crc >>> 0;
+}
+
+function encodeFrame(type, message) {
+  const frame = new Uint8Array(HEADER_SIZE + message.length);
+  const view = new DataView(frame.buffer);
+
+  frame.set(MAGIC, 0);
+  frame[4] = VERSION;
+  frame[5] = 0;
+  view.setUint16(6, type);
+  view.setUint32(8, message.length);
+  view.setUint32(12, crc32(0, frame.subarray(0, 12)));
+  view.setUint32(16, crc32(0, message));
+  frame.set(message, HEADER_SIZE);
+
+  return frame;
+}
+
+// decodeFrame returns the type and the message of a frame or null for frames that can not be read.
+function decodeFrame(frame) {
+  if (frame.length < HEADER_SIZE || MAGIC.some((b, i) => frame[i] !== b) || frame[4] !== VERSION || frame[5] !== 0) {
+    return null;
+  }
+
+  const view = new DataView(frame.buffer, frame.byteOffset, frame.byteLength);
+  const length = view.getUint32(8);
+
+  if (crc32(0, frame.subarray(0, 12)) !== view.getUint32(12) || frame.length !== HEADER_SIZE + length) {
+    return null;
+  }
+
+  const message = frame.subarray(HEADER_SIZE);
+
+  if (crc32(0, message) !== view.getUint32(16)) {
+    return null;
+  }
+
+  return {type: view.getUint16(6), message};
+}
+
+// Reader decodes the protobuf wire format, only the parts the messages of the protocol need.
+class Reader {
+  constructor(bytes) {
+    this.bytes = bytes;
+    this.view = new DataView(bytes.buffer, bytes.byteOffset, bytes.byteLength);
+    this.position = 0;
+  }
+
+  done() {
+    return this.position >= this.bytes.length;
+  }
+
+  // varint returns the value as number, negative int64 values included
+  varint() {
+    let low = 0;
+    let high = 0;
+    let shift = 0;
+
+    for (;;) {
+      const b = this.bytes[this.position++];
+
+      if (shift < 28) {
+        low |= (b & 0x7f) << shift;
+      } else if (shift === 28) {
+        low |= (b & 0x0f) << 28;
+        high |= (b & 0x7f) >> 4;
+      } else {
+        high |= (b & 0x7f) << (shift - 32);
+      }
+
+      shift += 7;
+
+      if (!(b & 0x80)) {
+        break;
+      }
+    }
+
+    return (high | 0) * 4294967296 + (low >>> 0);
+  }
+
+  bytesField() {
+    const length = this.varint();
+    const bytes = this.bytes.subarray(this.position, this.position + length);
+
+    this.position += length;
+
+    return bytes;
+  }
+
+  double() {
+    const value = this.view.getFloat64(this.position, true);
+
+    this.position += 8;
+
+    return value;
+  }
+
+  skip(wireType) {
+    switch (wireType) {
+      case 0:
+        this.varint();
+        break;
+      case 1:
+        this.position += 8;
+        break;
+      case 2:
+        this.bytesField();
+        break;
+      case 5:
+        this.position += 4;
+        break;
+      default:
+        throw new Error("unsupported wire type " + wireType);
+    }
+  }
+
+  // fields calls handler with the number and wire type of every field, the handler reads or skips the value
+  fields(handler) {
+    while (!this.done()) {
+      const tag = this.varint();
+
+      if (!handler.call(this, tag >>> 3, tag & 7)) {
+        this.skip(tag & 7);
+      }
+    }
+  }
+}
+
+// Writer encodes the protobuf wire format.
+class Writer {
+  constructor() {
+    this.bytes = [];
+  }
+
+  varint(value) {
+    while (value > 0x7f) {
+      this.bytes.push((value % 128) | 0x80);
+      value = Math.floor(value / 128);
+    }
+
+    this.bytes.push(value);
+  }
+
+  tag(field, wireType) {
+    this.varint((field << 3) | wireType);
+  }
+
+  message(field, bytes) {
+    this.tag(field, 2);
+    this.varint(bytes.length);
+    this.bytes.push(...bytes);
+  }
+
+  finish() {
+    return Uint8Array.from(this.bytes);
+  }
+}
+
+// readTimestamp returns a google.protobuf.Timestamp in milliseconds since the epoch.
+function readTimestamp(bytes) {
+  let seconds = 0;
+  let nanos = 0;
+
+  new Reader(bytes).fields(function (field, wireType) {
+    if (field === 1 && wireType === 0) {
+      seconds = this.varint();
+    } else if (field === 2 && wireType === 0) {
+      nanos = this.varint();
+    } else {
+      return false;
+    }
+
+    return true;
+  });
+
+  return seconds * 1000 + nanos / 1e6;
+}
+
+function writeTimestamp(milliseconds) {
+  const writer = new Writer();
+  const seconds = Math.floor(milliseconds / 1000);
+  const nanos = Math.round((milliseconds - seconds * 1000) * 1e6);
+
+  if (seconds) {
+    writer.tag(1, 0);
+    writer.varint(seconds);
+  }
+
+  if (nanos) {
+    writer.tag(2, 0);
+    writer.varint(nanos);
+  }
+
+  return writer.finish();
+}
+
+function readAudio(bytes) {
+  const audio = {time: 0, sampleRate: 0, channels: []};
+
+  new Reader(bytes).fields(function (field, wireType) {
+    if (field === 3 && wireType === 2) {
+      audio.time = readTimestamp(this.bytesField());
+    } else if (field === 5 && wireType === 2) {
+      audio.channels.push(readSamples(this.bytesField()));
+    } else if (field === 6 && wireType === 0) {
+      audio.sampleRate = this.varint();
+    } else {
+      return false;
+    }
+
+    return true;
+  });
+
+  return audio;
+}
+
+// readSamples reads the samples of a Channel, they are packed by the Go encoder.
+function readSamples(bytes) {
+  const samples = [];
+
+  new Reader(bytes).fields(function (field, wireType) {
+    if (field !== 1) {
+      return false;
+    }
+
+    if (wireType === 1) {
+      samples.push(this.double());
+
+      return true;
+    }
+
+    const packed = new Reader(this.bytesField());
+
+    while (!packed.done()) {
+      samples.push(packed.double());
+    }
+
+    return true;
+  });
+
+  return Float32Array.from(samples);
+}
+
+function readLatency(bytes) {
+  const latency = {time: 0, latency: 0};
+
+  new Reader(bytes).fields(function (field, wireType) {
+    if (field === 1 && wireType === 2) {
+      latency.time = readTimestamp(this.bytesField());
+    } else if (field === 2 && wireType === 0) {
+      latency.latency = this.varint();
+    } else {
+      return false;
+    }
+
+    return true;
+  });
+
+  return latency;
+}
+
+function readOutputLatency(bytes) {
+  const latency = {latency: 0, relative: false};
+
+  new Reader(bytes).fields(function (field, wireType) {
+    if (field === 1 && wireType === 0) {
+      latency.latency = this.varint();
+    } else if (field === 2 && wireType === 0) {
+      latency.relative = this.varint() !== 0;
+    } else {
+      return false;
+    }
+
+    return true;
+  });
+
+  return latency;
+}
+
+function readNowPlaying(bytes) {
+  const nowPlaying = {title: "", artist: ""};
+  const decoder = new TextDecoder();
+
+  new Reader(bytes).fields(function (field, wireType) {
+    if (field === 2 && wireType === 2) {
+      nowPlaying.title = decoder.decode(this.bytesField());
+    } else if (field === 3 && wireType === 2) {
+      nowPlaying.artist = decoder.decode(this.bytesField());
+    } else {
+      return false;
+    }
+
+    return true;
+  });
+
+  return nowPlaying;
+}
+
+// readEnvelope returns the field and the bytes of the message in the envelope.
+function readEnvelope(bytes) {
+  let envelope = null;
+
+  new Reader(bytes).fields(function (field, wireType) {
+    if (wireType !== 2) {
+      return false;
+    }
+
+    envelope = {field, message: this.bytesField()};
+
+    return true;
+  });
+
+  return envelope;
+}
+
+// Clock estimates the offset of the server clock from the time messages, like an NTP client.
+class Clock {
+  constructor() {
+    this.pending = [];
+    this.samples = [];
+    this.offset = null;
+    this.roundTrip = null;
+  }
+
+  now() {
+    return performance.timeOrigin + performance.now();
+  }
+
+  // sent remembers the send time of a time message, the server answers them in order
+  sent(time) {
+    this.pending.push(time);
+  }
+
+  received(latency) {
+    const t0 = this.pending.shift();
+
+    if (t0 === undefined) {
+      return;
+    }
+
+    // the server received the time message at t1 and answered at t2, both on its clock
+    const t3 = this.now();
+    const t1 = t0 + latency.latency / 1e6;
+    const t2 = latency.time;
+
+    this.samples.push({offset: (t1 - t0 + (t2 - t3)) / 2, roundTrip: t3 - t0 - (t2 - t1)});
+
+    if (this.samples.length > CLOCK_SAMPLES) {
+      this.samples.shift();
+    }
+
+    const best = this.samples.reduce((a, b) => (b.roundTrip < a.roundTrip ? b : a));
+
+    this.offset = best.offset;
+    this.roundTrip = best.roundTrip;
+  }
+
+  ready() {
+    return this.offset !== null;
+  }
+
+  // toLocal converts a time of the server clock to the local clock
+  toLocal(time) {
+    return time - this.offset;
+  }
+}
+
+// Player schedules the audio blocks at their play time on the output of an AudioContext.
+class Player {
+  constructor(context, clock) {
+    this.context = context;
+    this.clock = clock;
+    this.outputLatency = 0;
+    this.next = 0;
+    // the server time at which the scheduled audio ends, in milliseconds
+    this.end = 0;
+    this.played = 0;
+    this.dropped = 0;
+  }
+
+  // contextTime converts a local time in milliseconds to the time of the context at which it is audible
+  contextTime(local) {
+    const stamp = this.context.getOutputTimestamp();
+
+    if (!stamp.performanceTime) {
+      // the context has not started yet, estimate the output with the reported latency
+      const latency = this.context.outputLatency || this.context.baseLatency || 0;
+
+      return this.context.currentTime + (local - this.clock.now()) / 1000 - latency;
+    }
+
+    return stamp.contextTime + (local - performance.timeOrigin - stamp.performanceTime) / 1000;
+  }
+
+  play(audio) {
+    if (!this.clock.ready() || audio.channels.length === 0 || audio.channels[0].length === 0) {
+      return;
+    }
+
+    const rate = audio.sampleRate || 44100;
+    const frames = audio.channels[0].length;
+    const duration = frames / rate * 1000;
+
+    // a block that starts within the scheduled audio was received twice, e.g. in the history and the broadcast
+    if (audio.time + duration / 2 <= this.end) {
+      return;
+    }
+
+    let start = this.contextTime(this.clock.toLocal(audio.time)) - this.outputLatency / 1000;
+
+    // keep playing continuously while the block fits to the previous one, the estimates jitter a little
+    if (Math.abs(start - this.next) < DELAY_THRESHOLD) {
+      start = this.next;
+    }
+
+    if (start < this.context.currentTime + SCHEDULE_MARGIN) {
+      this.dropped += frames;
+
+      return;
+    }
+
+    const buffer = this.context.createBuffer(audio.channels.length, frames, rate);
+
+    audio.channels.forEach((samples, channel) => buffer.copyToChannel(samples, channel));
+
+    const source = this.context.createBufferSource();
+
+    source.buffer = buffer;
+    source.connect(this.context.destination);
+    source.start(start);
+
+    this.next = start + frames / rate;
+    this.end = audio.time + duration;
+    this.played += frames;
+  }
+}
+
+// Client connects to the WebSocket endpoint of the server and passes the messages to the player.
+class Client {
+  constructor(url, onStatus) {
+    this.url = url;
+    this.onStatus = onStatus;
+    this.clock = new Clock();
+    this.context = new AudioContext({latencyHint: "playback"});
+    this.player = new Player(this.context, this.clock);
+    this.nowPlaying = null;
+    this.timer = null;
+    this.closed = false;
+  }
+
+  connect() {
+    this.socket = new WebSocket(this.url);
+    this.socket.binaryType = "arraybuffer";
+
+    this.socket.onopen = () => {
+      this.status("connected");
+      this.timer = setInterval(() => this.sendTime(), TIME_INTERVAL);
+    };
+
+    this.socket.onmessage = (event) => this.receive(new Uint8Array(event.data));
+
+    this.socket.onclose = () => {
+      clearInterval(this.timer);
+      this.clock = new Clock();
+      this.player.clock = this.clock;
+
+      if (!this.closed) {
+        this.status("disconnected, reconnecting...");
+        setTimeout(() => this.connect(), 5000);
+      }
+    };
+  }
+
+  close() {
+    this.closed = true;
+    this.socket.close();
+    this.context.close();
+  }
+
+  status(text) {
+    if (this.onStatus) {
+      this.onStatus(text, this);
+    }
+  }
+
+  sendTime() {
+    const now = this.clock.now();
+    const time = new Writer();
+    const envelope = new Writer();
+
+    time.message(1, writeTimestamp(now));
+    envelope.message(ENVELOPE_TIME, time.finish());
+
+    this.clock.sent(now);
+    this.socket.send(encodeFrame(ENVELOPE_TYPE, envelope.finish()));
+  }
+
+  receive(data) {
+    const frame = decodeFrame(data);
+
+    // unwrapped messages of the single types are not sent by the server, frames of other types are skipped
+    if (frame === null || frame.type !== ENVELOPE_TYPE) {
+      return;
+    }
+
+    const envelope = readEnvelope(frame.message);
+
+    if (envelope === null) {
+      return;
+    }
+
+    switch (envelope.field) {
+      case ENVELOPE_AUDIO:
+        this.player.play(readAudio(envelope.message));
+        break;
+      case ENVELOPE_LATENCY:
+        this.clock.received(readLatency(envelope.message));
+        break;
+      case ENVELOPE_OUTPUT_LATENCY: {
+        const latency = readOutputLatency(envelope.message);
+
+        this.player.outputLatency = latency.latency / 1e6 + (latency.relative ? this.player.outputLatency : 0);
+        break;
+      }
+      case ENVELOPE_NOW_PLAYING:
+        this.nowPlaying = readNowPlaying(envelope.message);
+        this.status("playing");
+        break;
+    }
+  }
+}
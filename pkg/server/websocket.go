@@ -0,0 +1,329 @@
+package server
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/panjf2000/gnet/v2"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+
+	"network-audio/pkg/messages"
+)
+
+const webSocketWriteTimeout = time.Second * 5
+
+// web contains the browser client that is served next to the endpoint.
+//
+//go:embed web
+var web embed.FS
+
+// webSocketEndpoint speaks the framed protocol over WebSockets, one frame per binary message, so browsers can join
+// as clients. It serves the bundled browser client on "/" and the protocol on "/ws".
+type webSocketEndpoint struct {
+	logger   logrus.FieldLogger
+	server   *http.Server
+	upgrader *websocket.Upgrader
+
+	// answers the time messages and knows the output latencies of the hosts
+	s *Server
+
+	clients *sync.Map
+	counter uint64
+}
+
+// webSocketClient is the connection of the outbound of a web client. Its writer sends the frames of a write one per
+// message and then completes the write, so the frames of a slow client stay in the outbound like for TCP clients.
+type webSocketClient struct {
+	id         uint64
+	host       string
+	connection *websocket.Conn
+	outbound   *outbound
+	writes     chan webSocketWrite
+	done       chan struct{}
+
+	// the key of the subscriptions of the client at the server
+	key string
+}
+
+type webSocketWrite struct {
+	frames   [][]byte
+	callback gnet.AsyncCallback
+}
+
+func (c *webSocketClient) AsyncWrite(buf []byte, callback gnet.AsyncCallback) error {
+	return c.AsyncWritev([][]byte{buf}, callback)
+}
+
+// AsyncWritev hands the frames to the writer, the callback is called without a gnet connection once they are sent.
+func (c *webSocketClient) AsyncWritev(bs [][]byte, callback gnet.AsyncCallback) error {
+	select {
+	case c.writes <- webSocketWrite{frames: bs, callback: callback}:
+		return nil
+	case <-c.done:
+		return net.ErrClosed
+	}
+}
+
+// OutboundBuffered is always 0, the writer holds no frames after it completed a write.
+func (c *webSocketClient) OutboundBuffered() int {
+	return 0
+}
+
+func (c *webSocketClient) Close() error {
+	return c.connection.Close()
+}
+
+func (c *webSocketClient) RemoteAddr() net.Addr {
+	return c.connection.RemoteAddr()
+}
+
+// WithWebSocket serves the protocol over WebSockets and the browser client on the address, e.g. ":8080".
+func WithWebSocket(address string) Option {
+	return func(s *Server) {
+		s.webSocketAddress = address
+	}
+}
+
+func newWebSocketEndpoint(logger logrus.FieldLogger, address string, s *Server) *webSocketEndpoint {
+	e := &webSocketEndpoint{
+		logger: logger,
+		upgrader: &websocket.Upgrader{
+			ReadBufferSize:  4096,
+			WriteBufferSize: 64 * 1024,
+		},
+		s:       s,
+		clients: &sync.Map{},
+	}
+
+	files, err := fs.Sub(web, "web")
+
+	if err != nil {
+		panic(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(files)))
+	mux.HandleFunc("/ws", e.serveWebSocket)
+
+	e.server = &http.Server{Addr: address, Handler: mux}
+
+	return e
+}
+
+func (e *webSocketEndpoint) Start() {
+	e.logger.Infof("websocket endpoint is listening on %s", e.server.Addr)
+
+	go func() {
+		err := e.server.ListenAndServe()
+
+		if err != nil && err != http.ErrServerClosed {
+			e.logger.Errorf("websocket endpoint error: %s\n", err)
+		}
+	}()
+}
+
+func (e *webSocketEndpoint) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_ = e.server.Shutdown(ctx)
+
+	// hijacked connections are not closed by the shutdown
+	e.clients.Range(
+		func(key, value interface{}) bool {
+			_ = value.(*webSocketClient).connection.Close()
+
+			return true
+		},
+	)
+}
+
+// Send queues the frame of the message for all web clients, messages of a topic only for its subscribers. Like for
+// TCP clients the audio may be dropped while all other messages are guaranteed.
+func (e *webSocketEndpoint) Send(msg proto.Message, frame []byte) {
+	topic, hasTopic := topicOf(msg)
+
+	e.clients.Range(
+		func(key, value interface{}) bool {
+			client := value.(*webSocketClient)
+
+			if hasTopic && !e.s.subscribed(client.key, topic) {
+				return true
+			}
+
+			_ = client.outbound.queue(frame, deliveryOf(msg))
+
+			return true
+		},
+	)
+}
+
+// SendToHost queues the frame for all web clients of the host.
+func (e *webSocketEndpoint) SendToHost(host string, frame []byte, delivery Delivery) {
+	e.clients.Range(
+		func(key, value interface{}) bool {
+			client := value.(*webSocketClient)
+
+			if client.host == host {
+				_ = client.outbound.queue(frame, delivery)
+			}
+
+			return true
+		},
+	)
+}
+
+func (e *webSocketEndpoint) queueMessage(client *webSocketClient, msg proto.Message) {
+	frame, err := messages.ToPacket(msg).Bytes()
+
+	if err != nil {
+		e.logger.Errorf("packet to bytes error: %s\n", err)
+		return
+	}
+
+	_ = client.outbound.queue(frame, deliveryOf(msg))
+}
+
+func (e *webSocketEndpoint) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	connection, err := e.upgrader.Upgrade(w, r, nil)
+
+	if err != nil {
+		e.logger.Warnf("websocket upgrade error: %s", err)
+		return
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	id := atomic.AddUint64(&e.counter, 1)
+	client := &webSocketClient{
+		id:         id,
+		host:       host,
+		connection: connection,
+		// one write is in flight at a time
+		writes: make(chan webSocketWrite, 1),
+		done:   make(chan struct{}),
+		key:    fmt.Sprintf("web client %d", id),
+	}
+	client.outbound = newOutbound(e.logger, client, e.s.outboundDroppable, e.s.outboundGuaranteed, e.s.slowClientTimeout)
+
+	connection.SetReadLimit(messages.HeaderSize + messages.MaxFrameSize)
+
+	e.logger.Infof("web client %d connected: %s", client.id, r.RemoteAddr)
+
+	// like OnOpen, the broadcast waits until the client received the history
+	e.s.lock.Lock()
+
+	if latency, ok := e.s.outputLatencies.Load(host); ok {
+		e.queueMessage(client, &messages.OutputLatency{Latency: latency.(time.Duration).Nanoseconds()})
+	}
+
+	if nowPlaying := e.s.player.NowPlaying(); nowPlaying != nil {
+		e.queueMessage(client, nowPlaying)
+	}
+
+	e.clients.Store(client.id, client)
+
+	if e.s.history != nil {
+		for _, frame := range e.s.history.Pending(time.Now()) {
+			_ = client.outbound.queue(frame, Droppable)
+		}
+	}
+
+	e.s.lock.Unlock()
+
+	go e.write(client)
+
+	e.read(client)
+
+	e.clients.Delete(client.id)
+	e.s.subscriptions.Delete(client.key)
+	client.outbound.Close()
+	close(client.done)
+	_ = connection.Close()
+
+	e.logger.Infof("web client %d disconnected: %s", client.id, r.RemoteAddr)
+}
+
+// read handles the messages of the client until the connection fails.
+func (e *webSocketEndpoint) read(client *webSocketClient) {
+	for {
+		messageType, data, err := client.connection.ReadMessage()
+
+		if err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				e.logger.Warnf("web client %d read error: %s", client.id, err)
+			}
+
+			return
+		}
+
+		if messageType != websocket.BinaryMessage {
+			continue
+		}
+
+		msg, err := messages.Unmarshal(data)
+
+		if err != nil {
+			e.logger.Errorf("read message error: %s\n", err)
+			continue
+		}
+
+		switch m := msg.(type) {
+		case *messages.Time:
+			e.queueMessage(client, latencyOf(m))
+		case *messages.Subscription:
+			e.logger.Infof("web client %d subscribed to %v", client.id, m.Topics)
+			e.s.subscribe(client.key, m.Topics)
+		case *messages.OutputLatency:
+			if err := e.s.setOutputLatencyOf(client.host, m); err != nil {
+				e.logger.Errorf("set output latency error: %s\n", err)
+			}
+		default:
+			e.logger.Errorf("unknown message type: %T\n", m)
+		}
+	}
+}
+
+// write sends the frames of the writes of the outbound until the client is done or the connection fails.
+func (e *webSocketEndpoint) write(client *webSocketClient) {
+	for {
+		select {
+		case write := <-client.writes:
+			for _, frame := range write.frames {
+				// the outbound checks with an empty write whether a blocked client drained
+				if frame == nil {
+					continue
+				}
+
+				_ = client.connection.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout))
+				err := client.connection.WriteMessage(websocket.BinaryMessage, frame)
+
+				if err != nil {
+					e.logger.Warnf("web client %d write error: %s", client.id, err)
+
+					// unblocks the read
+					_ = client.connection.Close()
+
+					return
+				}
+			}
+
+			_ = write.callback(nil)
+		case <-client.done:
+			return
+		}
+	}
+}
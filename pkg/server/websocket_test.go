@@ -0,0 +1,356 @@
+package server
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+
+	"network-audio/pkg/messages"
+	"network-audio/pkg/timex"
+)
+
+type testWebClient struct {
+	t          *testing.T
+	connection *websocket.Conn
+}
+
+func (c *testWebClient) send(msg proto.Message) {
+	frame, err := messages.ToPacket(msg).Bytes()
+
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	if err := c.connection.WriteMessage(websocket.BinaryMessage, frame); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+func (c *testWebClient) read() proto.Message {
+	_ = c.connection.SetReadDeadline(time.Now().Add(time.Second * 5))
+	_, data, err := c.connection.ReadMessage()
+
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	msg, err := messages.Unmarshal(data)
+
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	return msg
+}
+
+// sync waits until the server has handled all messages sent before, the answer of a time message follows the frames
+// queued until then.
+func (c *testWebClient) sync() {
+	c.send(&messages.Time{Time: timex.ToTimestamp(time.Now())})
+
+	if msg := c.read(); !isLatency(msg) {
+		c.t.Fatalf("expected the latency, got %T", msg)
+	}
+}
+
+func isLatency(msg proto.Message) bool {
+	_, ok := msg.(*messages.Latency)
+
+	return ok
+}
+
+func testWebSocket(t *testing.T, options ...Option) (*Server, *httptest.Server, func() *testWebClient) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+
+	s := New(logger, ":0", append([]Option{WithWebSocket(":0")}, options...)...)
+	endpoint := httptest.NewServer(s.webSocket.server.Handler)
+
+	t.Cleanup(
+		func() {
+			s.webSocket.Close()
+			endpoint.Close()
+		},
+	)
+
+	dial := func() *testWebClient {
+		url := "ws" + strings.TrimPrefix(endpoint.URL, "http") + "/ws"
+		connection, _, err := websocket.DefaultDialer.Dial(url, nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() { _ = connection.Close() })
+
+		return &testWebClient{t: t, connection: connection}
+	}
+
+	return s, endpoint, dial
+}
+
+func testAudio() *messages.Audio {
+	m := messages.NewAudio(messages.ChannelLayout_CHANNEL_LAYOUT_MONO, make([]float64, 441))
+	m.SampleRate = 44100
+	m.Time = timex.ToTimestamp(time.Now().Add(time.Second))
+
+	return m
+}
+
+func TestWebSocket(t *testing.T) {
+	t.Run(
+		"should serve the browser client",
+		func(t *testing.T) {
+			_, endpoint, _ := testWebSocket(t)
+
+			response, err := endpoint.Client().Get(endpoint.URL + "/")
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_ = response.Body.Close()
+
+			if response.StatusCode != 200 {
+				t.Errorf("expected the index, got status %d", response.StatusCode)
+			}
+		},
+	)
+
+	t.Run(
+		"should send the output latency of the host on connect and answer the time",
+		func(t *testing.T) {
+			_, _, dial := testWebSocket(t, WithOutputLatency("127.0.0.1", time.Millisecond*20))
+			client := dial()
+
+			m, ok := client.read().(*messages.OutputLatency)
+
+			if !ok || m.Latency != (time.Millisecond*20).Nanoseconds() {
+				t.Fatalf("expected the output latency, got %v", m)
+			}
+
+			client.sync()
+		},
+	)
+
+	t.Run(
+		"should send the audio to all web clients",
+		func(t *testing.T) {
+			s, _, dial := testWebSocket(t, WithHistory(0))
+			clients := []*testWebClient{dial(), dial()}
+
+			for _, client := range clients {
+				client.sync()
+			}
+
+			audio := testAudio()
+
+			if err := s.Send(audio); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, client := range clients {
+				if m, ok := client.read().(*messages.Audio); !ok || !proto.Equal(m, audio) {
+					t.Errorf("expected the audio, got %v", m)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"should send the messages of a topic only to subscribers",
+		func(t *testing.T) {
+			s, _, dial := testWebSocket(t)
+			client := dial()
+			client.sync()
+
+			if err := s.Send(&messages.PlaybackState{}); err != nil {
+				t.Fatal(err)
+			}
+
+			// the latency is the next message without a subscription
+			client.sync()
+
+			client.send(&messages.Subscription{Topics: []messages.Topic{messages.Topic_TOPIC_PLAYBACK_STATE}})
+			client.sync()
+
+			if !s.subscribed("web client 1", messages.Topic_TOPIC_PLAYBACK_STATE) {
+				t.Error("expected the subscription at the server")
+			}
+
+			if err := s.Send(&messages.PlaybackState{}); err != nil {
+				t.Fatal(err)
+			}
+
+			if m, ok := client.read().(*messages.PlaybackState); !ok {
+				t.Errorf("expected the playback state, got %T", m)
+			}
+		},
+	)
+
+	t.Run(
+		"should send host messages only to the clients of the host",
+		func(t *testing.T) {
+			s, _, dial := testWebSocket(t)
+			client := dial()
+			client.sync()
+
+			if err := s.SetOutputLatency("192.0.2.1", time.Millisecond*30); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := s.SetOutputLatency("127.0.0.1", time.Millisecond*40); err != nil {
+				t.Fatal(err)
+			}
+
+			m, ok := client.read().(*messages.OutputLatency)
+
+			if !ok || m.Latency != (time.Millisecond*40).Nanoseconds() {
+				t.Errorf("expected the output latency of the host, got %v", m)
+			}
+		},
+	)
+
+	t.Run(
+		"should set the output latency of a host sent by a client",
+		func(t *testing.T) {
+			s, _, dial := testWebSocket(t, WithOutputLatency("127.0.0.1", time.Millisecond*20))
+			client := dial()
+			client.read()
+			client.sync()
+
+			client.send(&messages.OutputLatency{Latency: (time.Millisecond * 30).Nanoseconds(), Host: "192.0.2.1"})
+			client.send(&messages.OutputLatency{Latency: (time.Millisecond * 5).Nanoseconds(), Relative: true})
+
+			m, ok := client.read().(*messages.OutputLatency)
+
+			if !ok || m.Latency != (time.Millisecond*25).Nanoseconds() {
+				t.Errorf("expected the corrected output latency of the host, got %v", m)
+			}
+
+			if latency := s.GetOutputLatency("192.0.2.1"); latency != time.Millisecond*30 {
+				t.Errorf("expected the output latency of the other host, got %s", latency)
+			}
+		},
+	)
+
+	t.Run(
+		"should disconnect a client that stays behind",
+		func(t *testing.T) {
+			s, _, dial := testWebSocket(t, WithHistory(0), WithOutboundQueue(2, 1024), WithSlowClientTimeout(time.Millisecond*100))
+			client := dial()
+			client.sync()
+
+			// the client reads nothing, the audio fills the socket buffers and then the outbound
+			audio := messages.NewAudio(messages.ChannelLayout_CHANNEL_LAYOUT_MONO, make([]float64, 16*1024))
+			audio.SampleRate = 44100
+			deadline := time.Now().Add(time.Second * 10)
+
+			for webClients(s) > 0 {
+				if time.Now().After(deadline) {
+					t.Fatal("expected the client to be disconnected")
+				}
+
+				if err := s.Send(audio); err != nil {
+					t.Fatal(err)
+				}
+
+				time.Sleep(time.Millisecond)
+			}
+		},
+	)
+
+	t.Run(
+		"should close the connection of a client sending a message over the read limit",
+		func(t *testing.T) {
+			s, _, dial := testWebSocket(t)
+			client := dial()
+			client.sync()
+
+			large := make([]byte, messages.HeaderSize+messages.MaxFrameSize+1)
+			_ = client.connection.WriteMessage(websocket.BinaryMessage, large)
+
+			_ = client.connection.SetReadDeadline(time.Now().Add(time.Second * 5))
+
+			if _, _, err := client.connection.ReadMessage(); err == nil {
+				t.Error("expected the connection to be closed")
+			}
+
+			waitForWebClients(t, s, 0)
+		},
+	)
+
+	t.Run(
+		"should remove a client and its subscription when it disconnects",
+		func(t *testing.T) {
+			s, _, dial := testWebSocket(t)
+			client := dial()
+			client.send(&messages.Subscription{Topics: []messages.Topic{messages.Topic_TOPIC_PLAYBACK_STATE}})
+			client.sync()
+
+			waitForWebClients(t, s, 1)
+
+			_ = client.connection.Close()
+
+			waitForWebClients(t, s, 0)
+
+			if s.subscribed("web client 1", messages.Topic_TOPIC_PLAYBACK_STATE) {
+				t.Error("expected the subscription to be removed")
+			}
+		},
+	)
+
+	t.Run(
+		"should close the connections of the clients on close",
+		func(t *testing.T) {
+			s, _, dial := testWebSocket(t)
+			client := dial()
+			client.sync()
+
+			s.webSocket.Close()
+
+			_ = client.connection.SetReadDeadline(time.Now().Add(time.Second * 5))
+
+			if _, _, err := client.connection.ReadMessage(); err == nil {
+				t.Error("expected the connection to be closed")
+			}
+		},
+	)
+}
+
+func webClients(s *Server) int {
+	count := 0
+
+	s.webSocket.clients.Range(
+		func(key, value interface{}) bool {
+			count++
+
+			return true
+		},
+	)
+
+	return count
+}
+
+func waitForWebClients(t *testing.T, s *Server, expected int) {
+	deadline := time.Now().Add(time.Second * 5)
+
+	for {
+		count := webClients(s)
+
+		if count == expected {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d web clients, got %d", expected, count)
+		}
+
+		time.Sleep(time.Millisecond * 10)
+	}
+}
@@ -0,0 +1,142 @@
+// Package wavx writes PCM WAV streams, either as files or as endless streams to unseekable writers like HTTP responses.
+package wavx
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/faiface/beep"
+	"github.com/pkg/errors"
+)
+
+const headerSize = 44
+
+// StreamingSize is used as size in the header while the final size is unknown, players read until the end of the stream.
+const StreamingSize = 0xFFFFFFFF
+
+// maxDataSize is the most data the 32 bit sizes of the header can describe, below the StreamingSize.
+const maxDataSize = StreamingSize - 1 - (headerSize - 8)
+
+// ErrTooLarge is returned by Writer.Write for samples that would exceed the size a WAV file can describe, about
+// 4 GiB. Nothing is written then, a recording continues in a new file.
+var ErrTooLarge = errors.New("wav data exceeds the size of a wav file")
+
+// MaxFrames returns the amount of frames of the format that fit into a WAV file.
+func MaxFrames(format beep.Format) int64 {
+	return maxDataSize / int64(format.Width())
+}
+
+// Header returns the header of a PCM WAV file with the given amount of data bytes.
+func Header(format beep.Format, dataSize uint32) []byte {
+	header := make([]byte, headerSize)
+	bytesPerFrame := format.Width()
+
+	riffSize := uint32(StreamingSize)
+
+	if dataSize != StreamingSize {
+		riffSize = dataSize + headerSize - 8
+	}
+
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], riffSize)
+	copy(header[8:], "WAVE")
+	copy(header[12:], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:], 16)
+	binary.LittleEndian.PutUint16(header[20:], 1)
+	binary.LittleEndian.PutUint16(header[22:], uint16(format.NumChannels))
+	binary.LittleEndian.PutUint32(header[24:], uint32(format.SampleRate))
+	binary.LittleEndian.PutUint32(header[28:], uint32(int(format.SampleRate)*bytesPerFrame))
+	binary.LittleEndian.PutUint16(header[32:], uint16(bytesPerFrame))
+	binary.LittleEndian.PutUint16(header[34:], uint16(format.Precision*8))
+	copy(header[36:], "data")
+	binary.LittleEndian.PutUint32(header[40:], dataSize)
+
+	return header
+}
+
+// Encode converts the samples to interleaved PCM data in the format, 8 bit WAV data is unsigned.
+func Encode(format beep.Format, samples [][2]float64) []byte {
+	data := make([]byte, len(samples)*format.Width())
+	p := data
+
+	for _, sample := range samples {
+		if format.Precision == 1 {
+			p = p[format.EncodeUnsigned(p, sample):]
+		} else {
+			p = p[format.EncodeSigned(p, sample):]
+		}
+	}
+
+	return data
+}
+
+// Writer writes samples as WAV, the sizes in the header are fixed on Close if the underlying writer can seek.
+type Writer struct {
+	writer io.Writer
+	format beep.Format
+
+	written     uint64
+	maxDataSize uint64
+}
+
+func NewWriter(writer io.Writer, format beep.Format) (*Writer, error) {
+	if format.NumChannels < 1 || format.NumChannels > 2 || format.Precision < 1 || format.Precision > 3 {
+		return nil, errors.Errorf("unsupported wav format: %d channels, %d bytes precision", format.NumChannels, format.Precision)
+	}
+
+	_, err := writer.Write(Header(format, StreamingSize))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Writer{writer: writer, format: format, maxDataSize: maxDataSize}, nil
+}
+
+func (w *Writer) Format() beep.Format {
+	return w.format
+}
+
+// Frames returns the amount of frames written so far.
+func (w *Writer) Frames() int {
+	return int(w.written) / w.format.Width()
+}
+
+// Write appends the samples, it fails with ErrTooLarge and writes nothing if they do not fit into the file.
+func (w *Writer) Write(samples [][2]float64) error {
+	if w.written+uint64(len(samples)*w.format.Width()) > w.maxDataSize {
+		return ErrTooLarge
+	}
+
+	n, err := w.writer.Write(Encode(w.format, samples))
+	w.written += uint64(n)
+
+	return err
+}
+
+// Close writes the final sizes into the header and closes the underlying writer if it is a closer.
+func (w *Writer) Close() error {
+	if seeker, ok := w.writer.(io.WriteSeeker); ok {
+		header := Header(w.format, uint32(w.written))
+
+		_, err := seeker.Seek(0, io.SeekStart)
+
+		if err == nil {
+			_, err = seeker.Write(header)
+		}
+
+		if err == nil {
+			_, err = seeker.Seek(0, io.SeekEnd)
+		}
+
+		if err != nil {
+			return errors.Wrap(err, "error writing wav header")
+		}
+	}
+
+	if closer, ok := w.writer.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
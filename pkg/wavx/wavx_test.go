@@ -0,0 +1,53 @@
+package wavx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/faiface/beep"
+)
+
+func TestWriter_Write(t *testing.T) {
+	format := beep.Format{SampleRate: 44100, NumChannels: 2, Precision: 2}
+
+	t.Run(
+		"should fail with ErrTooLarge instead of wrapping the size",
+		func(t *testing.T) {
+			buffer := &bytes.Buffer{}
+			w, err := NewWriter(buffer, format)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// room for 10 frames
+			w.maxDataSize = uint64(10 * format.Width())
+
+			if err := w.Write(make([][2]float64, 8)); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := w.Write(make([][2]float64, 4)); !errors.Is(err, ErrTooLarge) {
+				t.Fatalf("expected ErrTooLarge, got %v", err)
+			}
+
+			if w.Frames() != 8 || buffer.Len() != headerSize+8*format.Width() {
+				t.Errorf("expected 8 frames to be written, got %d frames and %d bytes", w.Frames(), buffer.Len())
+			}
+		},
+	)
+
+	t.Run(
+		"should describe the largest file in the header",
+		func(t *testing.T) {
+			header := Header(format, uint32(MaxFrames(format)*int64(format.Width())))
+			riffSize := binary.LittleEndian.Uint32(header[4:])
+
+			if riffSize == StreamingSize || riffSize < binary.LittleEndian.Uint32(header[40:]) {
+				t.Errorf("expected the riff size to fit, got %#x", riffSize)
+			}
+		},
+	)
+}
@@ -8,4 +8,11 @@ DIST_DIR="$ROOT_DIRECTORY/pkg"
 protoc -I="$SRC_DIR" --go_out="$DIST_DIR" "$SRC_DIR/audio.proto"
 protoc -I="$SRC_DIR" --go_out="$DIST_DIR" "$SRC_DIR/time.proto"
 protoc -I="$SRC_DIR" --go_out="$DIST_DIR" "$SRC_DIR/latency.proto"
-protoc -I="$SRC_DIR" --go_out="$DIST_DIR" "$SRC_DIR/command.proto"
\ No newline at end of file
+protoc -I="$SRC_DIR" --go_out="$DIST_DIR" "$SRC_DIR/command.proto"
+protoc -I="$SRC_DIR" --go_out="$DIST_DIR" "$SRC_DIR/output_latency.proto"
+protoc -I="$SRC_DIR" --go_out="$DIST_DIR" "$SRC_DIR/calibration.proto"
+protoc -I="$SRC_DIR" --go_out="$DIST_DIR" "$SRC_DIR/now_playing.proto"
+protoc -I="$SRC_DIR" --go_out="$DIST_DIR" "$SRC_DIR/playback_state.proto"
+protoc -I="$SRC_DIR" --go_out="$DIST_DIR" "$SRC_DIR/subscription.proto"
+protoc -I="$SRC_DIR" --go_out="$DIST_DIR" "$SRC_DIR/dsp_config.proto"
+protoc -I="$SRC_DIR" --go_out="$DIST_DIR" "$SRC_DIR/envelope.proto"